@@ -0,0 +1,171 @@
+// Go Concurrency Essentials - Resizable and Adaptive Concurrency Limits
+// Description: A task runner whose concurrency limit changes at runtime
+//              Part 1 resizes the limit by hand while tasks are in flight;
+//              Part 2 lets an AIMD limiter tune itself against a backend
+//              whose capacity drops halfway through the run
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"essentials/limiter"
+	"essentials/pool"
+)
+
+// ==================== PART 1: MANUAL RESIZE ====================
+
+// manualResize grows and then shrinks the limit while tasks run
+func manualResize(ctx context.Context) {
+	fmt.Println("Part 1: manual resize")
+	lim := limiter.New(2)
+	workers := pool.NewWithLimiter(ctx, lim)
+	start := time.Now()
+
+	report := func(what string) {
+		fmt.Printf("  %6v  %-34s limit=%d in-flight=%d waiting=%d\n",
+			time.Since(start).Round(10*time.Millisecond), what, lim.Limit(), lim.InFlight(), lim.Waiting())
+	}
+
+	// Submit from a separate goroutine: Submit blocks while the pool is full
+	go func() {
+		for range 16 {
+			err := workers.Submit(ctx, func(ctx context.Context) error {
+				time.Sleep(300 * time.Millisecond)
+				return nil
+			})
+			if err != nil {
+				log.Print(err)
+				return
+			}
+		}
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	report("started with limit 2")
+
+	lim.SetLimit(8)
+	time.Sleep(10 * time.Millisecond)
+	report("raised to 8 (waiters admitted)")
+
+	// Shrinking does not interrupt running tasks: Resize waits for them
+	resizeStart := time.Now()
+	if err := lim.Resize(ctx, 3); err != nil {
+		log.Fatal(err)
+	}
+	report(fmt.Sprintf("shrunk to 3 (waited %v)", time.Since(resizeStart).Round(10*time.Millisecond)))
+
+	time.Sleep(10 * time.Millisecond)
+	for lim.InFlight()+lim.Waiting() > 0 {
+		if lim.InFlight() > lim.Limit() {
+			fmt.Println("  ERROR: in-flight exceeds limit after Resize")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := workers.Wait(); err != nil {
+		log.Fatal(err)
+	}
+	report("all tasks done")
+	fmt.Println()
+}
+
+// ==================== PART 2: ADAPTIVE (AIMD) ====================
+
+// backend simulates a service with a fixed number of servers: calls beyond
+// its capacity queue, so latency rises once the client sends too many
+type backend struct {
+	servers *limiter.Limiter
+	service time.Duration
+}
+
+// call waits for a free server and then takes service time
+func (b *backend) call(ctx context.Context) error {
+	if err := b.servers.Acquire(ctx); err != nil {
+		return err
+	}
+	defer b.servers.Release()
+	time.Sleep(b.service)
+	return nil
+}
+
+// adaptive runs tasks for a while under an AIMD limiter and traces the limit
+func adaptive(ctx context.Context) {
+	const (
+		service   = 20 * time.Millisecond
+		runFor    = 3 * time.Second
+		degradeAt = runFor / 2
+	)
+	be := &backend{servers: limiter.New(8), service: service}
+	lim := limiter.NewAdaptive(limiter.AIMDConfig{
+		Initial: 1,
+		Min:     1,
+		Max:     32,
+		Target:  service * 3 / 2, // Anything slower than 1.5x service time is queueing
+		Backoff: 0.75,
+	})
+
+	fmt.Println("Part 2: AIMD limit against a backend with 8 servers (4 after halfway)")
+	fmt.Printf("  target latency %v, service time %v\n", service*3/2, service)
+
+	runCtx, cancel := context.WithTimeout(ctx, runFor)
+	defer cancel()
+	workers := pool.NewWithLimiter(runCtx, lim)
+	var completed atomic.Int64
+	start := time.Now()
+
+	// Trace the limit every 150ms, halving the backend halfway through
+	traceDone := make(chan struct{})
+	go func() {
+		defer close(traceDone)
+		degraded := false
+		ticker := time.NewTicker(150 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if !degraded && time.Since(start) >= degradeAt {
+				be.servers.SetLimit(4)
+				degraded = true
+				fmt.Println("  ---- backend capacity drops to 4 ----")
+			}
+			l := lim.Limit()
+			fmt.Printf("  %5v  limit %2d %s\n",
+				time.Since(start).Round(10*time.Millisecond), l, strings.Repeat("#", l))
+		}
+	}()
+
+	// Keep the pool saturated until the run ends
+	for runCtx.Err() == nil {
+		err := workers.Submit(runCtx, func(ctx context.Context) error {
+			if be.call(ctx) == nil {
+				completed.Add(1)
+			}
+			return nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	workers.Wait()
+	<-traceDone
+
+	elapsed := time.Since(start)
+	fmt.Printf("  %d tasks in %v (%.0f/s), final limit %d\n",
+		completed.Load(), elapsed.Round(10*time.Millisecond),
+		float64(completed.Load())/elapsed.Seconds(), lim.Limit())
+}
+
+// main runs both parts
+func main() {
+	ctx := context.Background()
+	manualResize(ctx)
+	adaptive(ctx)
+}
@@ -0,0 +1,113 @@
+// Go Concurrency Essentials - Arbitrary-Precision Collatz
+// Description: Computes Collatz trajectories for very large starting values
+//              using the math/big engine, one pool worker per input
+//
+// Inputs are decimal integers or powers written as base^exp, optionally
+// followed by +offset or -offset, e.g. 2^1000+1 or 27
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+
+	"essentials/collatz"
+	"essentials/pool"
+)
+
+// parseBig parses a decimal integer or base^exp[(+|-)offset]
+// Parameters:
+//   - s: Input text
+//
+// Returns:
+//   - The value, or an error describing what could not be parsed
+func parseBig(s string) (*big.Int, error) {
+	base, rest, isPower := strings.Cut(s, "^")
+	if !isPower {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("not an integer: %q", s)
+		}
+		return n, nil
+	}
+
+	// Split the exponent from an optional trailing offset
+	exp, offset := rest, "0"
+	if i := strings.IndexAny(rest, "+-"); i > 0 {
+		exp, offset = rest[:i], rest[i:]
+	}
+
+	b, ok1 := new(big.Int).SetString(base, 10)
+	e, ok2 := new(big.Int).SetString(exp, 10)
+	o, ok3 := new(big.Int).SetString(strings.TrimPrefix(offset, "+"), 10)
+	if !ok1 || !ok2 || !ok3 || e.Sign() < 0 {
+		return nil, fmt.Errorf("not base^exp[+offset]: %q", s)
+	}
+	n := new(big.Int).Exp(b, e, nil)
+	return n.Add(n, o), nil
+}
+
+// abbreviate shortens long numbers to their first and last digits
+func abbreviate(n *big.Int) string {
+	s := n.String()
+	if len(s) <= 40 {
+		return s
+	}
+	return fmt.Sprintf("%s...%s (%d digits)", s[:15], s[len(s)-15:], len(s))
+}
+
+// main analyzes every command-line input concurrently
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: collatz-big N [N...]   (N = 27, 2^1000+1, 10^300-1, ...)")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	workers := pool.New(ctx, runtime.GOMAXPROCS(0))
+	inputs := make([]*big.Int, flag.NArg())
+	out := make([]collatz.Trajectory, flag.NArg())
+	errs := make([]error, flag.NArg())
+
+	for i, arg := range flag.Args() {
+		n, err := parseBig(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inputs[i] = n
+
+		// Each trajectory is independent: record its error rather than
+		// returning it, so one bad input does not cancel the rest
+		if err := workers.Submit(ctx, func(ctx context.Context) error {
+			out[i], errs[i] = collatz.Analyze(n)
+			return nil
+		}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := workers.Wait(); err != nil {
+		log.Fatal(err)
+	}
+
+	for i, n := range inputs {
+		fmt.Printf("n = %s\n", abbreviate(n))
+		if errs[i] != nil {
+			fmt.Printf("  error: %v\n\n", errs[i])
+			continue
+		}
+		fmt.Printf("  steps:         %d\n", out[i].Steps)
+		fmt.Printf("  stopping time: %d\n", out[i].StoppingTime)
+		fmt.Printf("  peak:          %s\n\n", abbreviate(out[i].Peak))
+	}
+}
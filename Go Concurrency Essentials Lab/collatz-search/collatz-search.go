@@ -0,0 +1,279 @@
+// Go Concurrency Essentials - Parallel Collatz Range Search
+// Description: Searches every n in [1, limit] for record Collatz trajectories
+//              (most steps, highest peak) using the worker pool
+//
+// - The range is split into fixed-size chunks, one pool task per chunk
+// - Workers share a memo cache of known step counts and peaks
+// - Progress is checkpointed to a file, so an interrupted run (Ctrl-C)
+//   resumes from the last fully completed chunk
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"time"
+
+	"essentials/collatz"
+	"essentials/pool"
+)
+
+// ==================== RECORDS ====================
+// Record is the best value found so far for one criterion
+type Record struct {
+	N     uint64 `json:"n"`
+	Steps int    `json:"steps"`
+	Peak  uint64 `json:"peak"`
+}
+
+// Records holds the record-holders of a search
+type Records struct {
+	MaxSteps Record `json:"max_steps"`
+	MaxPeak  Record `json:"max_peak"`
+}
+
+// merge folds other into r. Ties go to the smaller n, so merging the same
+// chunk twice (after a resume) gives the same answer
+func (r *Records) merge(other Records) {
+	if better(uint64(other.MaxSteps.Steps), other.MaxSteps.N, uint64(r.MaxSteps.Steps), r.MaxSteps.N) {
+		r.MaxSteps = other.MaxSteps
+	}
+	if better(other.MaxPeak.Peak, other.MaxPeak.N, r.MaxPeak.Peak, r.MaxPeak.N) {
+		r.MaxPeak = other.MaxPeak
+	}
+}
+
+// better reports whether candidate (value a at n) beats current (value b at m)
+// n or m of 0 means "no record yet"
+func better(a, n, b, m uint64) bool {
+	if n == 0 {
+		return false
+	}
+	return m == 0 || a > b || (a == b && n < m)
+}
+
+// =================================================
+
+// ==================== CHECKPOINT ====================
+// Checkpoint is the resumable state of a search
+// Next is a watermark: every chunk starting below it is finished
+type Checkpoint struct {
+	Limit     uint64  `json:"limit"`
+	ChunkSize uint64  `json:"chunk_size"`
+	Next      uint64  `json:"next"`
+	Records   Records `json:"records"`
+}
+
+// loadCheckpoint reads a checkpoint, returning nil if the file does not exist
+func loadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// save writes the checkpoint atomically (temp file + rename), so a crash
+// mid-write never leaves a truncated checkpoint behind
+func (cp Checkpoint) save(path string) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ====================================================
+
+// ==================== SEARCH STATE ====================
+// search tracks finished chunks and the records found so far
+type search struct {
+	mutex   sync.Mutex
+	cp      Checkpoint      // Watermark and records
+	done    map[uint64]bool // Finished chunks at or above the watermark
+	checked uint64          // Numbers checked in this run
+}
+
+// finish records a completed chunk and advances the watermark past every
+// contiguous finished chunk
+func (s *search) finish(start uint64, records Records, count uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cp.Records.merge(records)
+	s.checked += count
+	s.done[start] = true
+	for s.done[s.cp.Next] {
+		delete(s.done, s.cp.Next)
+		s.cp.Next += s.cp.ChunkSize
+	}
+}
+
+// snapshot returns a copy of the checkpoint and the count checked so far
+func (s *search) snapshot() (Checkpoint, uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cp, s.checked
+}
+
+// ======================================================
+
+// searchChunk finds the records among n in [start, end)
+// Checks ctx every few thousand numbers so Ctrl-C stops promptly; an
+// interrupted chunk is not reported and will be redone on resume
+func searchChunk(ctx context.Context, memo *collatz.Memo, start, end uint64) (Records, error) {
+	var best Records
+	for n := start; n < end; n++ {
+		if n%4096 == 0 && ctx.Err() != nil {
+			return Records{}, ctx.Err()
+		}
+		steps, peak, err := memo.Trajectory(n)
+		if err != nil {
+			return Records{}, err
+		}
+		best.merge(Records{
+			MaxSteps: Record{N: n, Steps: steps, Peak: peak},
+			MaxPeak:  Record{N: n, Steps: steps, Peak: peak},
+		})
+	}
+	return best, nil
+}
+
+// main runs (or resumes) the search
+func main() {
+	limit := flag.Uint64("limit", 100_000_000, "search every n in [1, limit]")
+	chunkSize := flag.Uint64("chunk", 1_000_000, "numbers per pool task")
+	cacheSize := flag.Int("cache", 1<<22, "memo cache entries (12 bytes each)")
+	workersN := flag.Int("workers", runtime.GOMAXPROCS(0), "concurrent chunks")
+	checkpointPath := flag.String("checkpoint", "collatz-search.json", "checkpoint file (empty to disable)")
+	interval := flag.Duration("interval", 5*time.Second, "checkpoint and progress interval")
+	flag.Parse()
+	if *chunkSize < 1 {
+		log.Fatal("-chunk must be at least 1") // A zero step never advances past the first chunk
+	}
+	if *cacheSize < 0 {
+		log.Fatal("-cache must not be negative") // 0 disables the memo cache
+	}
+	if *interval <= 0 {
+		log.Fatal("-interval must be positive") // time.NewTicker panics otherwise
+	}
+
+	// ==================== RESUME ====================
+	s := &search{
+		cp:   Checkpoint{Limit: *limit, ChunkSize: *chunkSize, Next: 1},
+		done: make(map[uint64]bool),
+	}
+	if *checkpointPath != "" {
+		cp, err := loadCheckpoint(*checkpointPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch {
+		case cp == nil:
+		case cp.Limit != *limit || cp.ChunkSize != *chunkSize:
+			log.Fatalf("%s is for -limit %d -chunk %d; delete it or use those flags",
+				*checkpointPath, cp.Limit, cp.ChunkSize)
+		default:
+			s.cp = *cp
+			fmt.Printf("Resuming from n = %d\n", cp.Next)
+		}
+	}
+	// ================================================
+
+	// Ctrl-C cancels the pool; finished chunks are kept in the checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	memo := collatz.NewMemo(*cacheSize)
+	if err := memo.Warm(s.cp.Next); err != nil {
+		log.Fatal(err)
+	}
+	workers := pool.New(ctx, *workersN)
+	begin := time.Now()
+
+	// Periodic checkpoint and progress report
+	saveCheckpoint := func() {
+		cp, checked := s.snapshot()
+		if *checkpointPath != "" {
+			if err := cp.save(*checkpointPath); err != nil {
+				log.Printf("checkpoint: %v", err)
+			}
+		}
+		rate := float64(checked) / time.Since(begin).Seconds()
+		fmt.Printf("  %d/%d (%.1f%%), %.1fM n/s, max steps %d at %d\n",
+			min(cp.Next-1, cp.Limit), cp.Limit, 100*float64(min(cp.Next-1, cp.Limit))/float64(cp.Limit),
+			rate/1e6, cp.Records.MaxSteps.Steps, cp.Records.MaxSteps.N)
+	}
+	ticker := time.NewTicker(*interval)
+	tickerDone := make(chan struct{})
+	var reporter sync.WaitGroup
+	reporter.Go(func() {
+		for {
+			select {
+			case <-ticker.C:
+				saveCheckpoint()
+			case <-tickerDone:
+				return
+			}
+		}
+	})
+
+	fmt.Printf("Searching [1, %d] in chunks of %d with %d workers\n", *limit, *chunkSize, *workersN)
+
+	// ==================== SUBMIT CHUNKS ====================
+	for start := s.cp.Next; start <= *limit; start += *chunkSize {
+		end := min(start+*chunkSize, *limit+1)
+		err := workers.Submit(ctx, func(ctx context.Context) error {
+			records, err := searchChunk(ctx, memo, start, end)
+			if err != nil {
+				return err
+			}
+			s.finish(start, records, end-start)
+			return nil
+		})
+		if err != nil {
+			break // Interrupted, or a chunk failed
+		}
+	}
+	err := workers.Wait()
+	// =======================================================
+
+	ticker.Stop()
+	close(tickerDone)
+	reporter.Wait()
+	saveCheckpoint()
+
+	switch {
+	case ctx.Err() != nil:
+		fmt.Printf("\nInterrupted; rerun the same command to resume from %s\n", *checkpointPath)
+		os.Exit(130)
+	case err != nil:
+		log.Fatal(err)
+	}
+
+	cp, _ := s.snapshot()
+	fmt.Printf("\nDone in %v\n", time.Since(begin).Round(time.Millisecond))
+	fmt.Printf("Most steps:   n = %d, %d steps (peak %d)\n",
+		cp.Records.MaxSteps.N, cp.Records.MaxSteps.Steps, cp.Records.MaxSteps.Peak)
+	fmt.Printf("Highest peak: n = %d, peak %d (%d steps)\n",
+		cp.Records.MaxPeak.N, cp.Records.MaxPeak.Peak, cp.Records.MaxPeak.Steps)
+}
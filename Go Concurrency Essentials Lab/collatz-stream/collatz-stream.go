@@ -0,0 +1,108 @@
+// Go Concurrency Essentials - Streaming Collatz
+// Description: Computes Collatz steps for an input stream of unknown size
+//              using stream.ParallelMap instead of a pre-sized slice
+//
+// Inputs come from stdin (one number per line) or from an endless counter.
+// After -take results the consumer cancels the context; the program then
+// checks that every pipeline goroutine has exited
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"essentials/collatz"
+	"essentials/stream"
+)
+
+// main streams inputs through ParallelMap and prints results
+func main() {
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "concurrent workers")
+	ordered := flag.Bool("ordered", true, "emit results in input order")
+	window := flag.Int("window", 16, "reorder window when -ordered")
+	take := flag.Int("take", 20, "stop after this many results (0 = until input ends)")
+	start := flag.Int("start", 1, "first value of the counter when not reading stdin")
+	fromStdin := flag.Bool("stdin", false, "read inputs from stdin, one per line")
+	flag.Parse()
+
+	baseline := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// ==================== INPUT (size unknown) ====================
+	var inputs <-chan int
+	if *fromStdin {
+		scanner := bufio.NewScanner(os.Stdin)
+		inputs = stream.Generate(ctx, func() (int, bool) {
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				n, err := strconv.Atoi(line)
+				if err != nil {
+					log.Printf("skipping %q: %v", line, err)
+					continue
+				}
+				return n, true
+			}
+			return 0, false
+		})
+	} else {
+		n := *start - 1
+		inputs = stream.Generate(ctx, func() (int, bool) {
+			n++
+			return n, true // Endless: only cancellation stops it
+		})
+	}
+	// ==============================================================
+
+	var opts []stream.Option
+	if *ordered {
+		opts = append(opts, stream.Ordered(*window))
+	}
+	results := stream.ParallelMap(ctx, inputs, *workers,
+		func(ctx context.Context, n int) collatz.Result {
+			return collatz.Compute(n)
+		}, opts...)
+
+	// ==================== CONSUME ====================
+	got := 0
+	for r := range results {
+		if r.Err != nil {
+			fmt.Printf("%d: %v\n", r.Input, r.Err)
+		} else {
+			fmt.Printf("%d: %d steps (peak %s)\n", r.Input, r.Steps, r.Peak)
+		}
+		got++
+		if *take > 0 && got == *take {
+			break // Stop early: cancel below shuts the pipeline down
+		}
+	}
+	cancel()
+	// =================================================
+
+	// ==================== LEAK CHECK ====================
+	// Give cancelled goroutines a moment to observe ctx.Done()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	fmt.Printf("\n%d results; goroutines: %d before, %d after cancel\n",
+		got, baseline, runtime.NumGoroutine())
+	if runtime.NumGoroutine() > baseline {
+		// A goroutine blocked reading stdin cannot be interrupted
+		if !*fromStdin {
+			fmt.Println("LEAK: pipeline goroutines still running")
+			os.Exit(1)
+		}
+	}
+}
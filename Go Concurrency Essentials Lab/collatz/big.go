@@ -0,0 +1,126 @@
+// Go Concurrency Essentials - Arbitrary-Precision Collatz Engine
+// Description: Follows a trajectory in uint64 arithmetic while values fit,
+//              switching to math/big transparently once they exceed 64 bits
+//              (and back again when they shrink), so starting values with
+//              hundreds of digits never overflow
+
+package collatz
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Trajectory summarises the path from a starting value down to 1
+type Trajectory struct {
+	Steps        int      // Total steps to reach 1
+	StoppingTime int      // Steps until the value first drops below the start (0 for 1)
+	Peak         *big.Int // Largest value reached, including the start
+}
+
+// limit64 is the largest odd value whose 3n+1 still fits in a uint64
+const limit64 = (math.MaxUint64 - 1) / 3
+
+// Analyze computes the full trajectory of n
+// Parameters:
+//   - n: Starting number (must be positive; not modified)
+//
+// Returns:
+//   - Step count, stopping time and peak value
+//   - ErrNonPositive for n <= 0
+func Analyze(n *big.Int) (Trajectory, error) {
+	if n.Sign() <= 0 {
+		return Trajectory{}, fmt.Errorf("%w: %s", ErrNonPositive, n)
+	}
+
+	var (
+		t         = Trajectory{Peak: new(big.Int).Set(n)}
+		start     = new(big.Int).Set(n)
+		stopped   = n.Cmp(big.NewInt(1)) == 0 // 1 has stopping time 0
+		startFits = n.IsUint64()
+		start64   = n.Uint64()
+
+		small   uint64   // Current value while it fits in 64 bits
+		large   *big.Int // Current value once it does not
+		peak64  uint64   // Peak while every value so far fitted
+		isSmall = startFits
+	)
+	if isSmall {
+		small, peak64 = start64, start64
+	} else {
+		large = new(big.Int).Set(n)
+	}
+
+	for {
+		if isSmall {
+			// ==================== 64-BIT PATH ====================
+			if small == 1 {
+				break
+			}
+			if small%2 == 0 {
+				small /= 2
+			} else if small > limit64 {
+				// 3n+1 would overflow: continue in big arithmetic
+				large = new(big.Int).SetUint64(small)
+				isSmall = false
+				continue
+			} else {
+				small = 3*small + 1
+				if small > peak64 {
+					peak64 = small
+				}
+			}
+			t.Steps++
+			if !stopped && startFits && small < start64 {
+				stopped, t.StoppingTime = true, t.Steps
+			}
+			// =====================================================
+		} else {
+			// ==================== BIG PATH ====================
+			if large.Bit(0) == 0 {
+				large.Rsh(large, 1)
+			} else {
+				tripled := new(big.Int).Lsh(large, 1)
+				large.Add(large, tripled).Add(large, big.NewInt(1))
+				if large.Cmp(t.Peak) > 0 {
+					t.Peak.Set(large)
+				}
+			}
+			t.Steps++
+			if !stopped && large.Cmp(start) < 0 {
+				stopped, t.StoppingTime = true, t.Steps
+			}
+			if large.IsUint64() {
+				// Back in range: drop to the fast path
+				small, isSmall = large.Uint64(), true
+			}
+			// ==================================================
+		}
+	}
+
+	// The small path tracks its own peak; merge it in
+	if p := new(big.Int).SetUint64(peak64); p.Cmp(t.Peak) > 0 {
+		t.Peak = p
+	}
+	return t, nil
+}
+
+// ComputeBig is Compute using the arbitrary-precision engine
+// It never reports ErrOverflow
+func ComputeBig(n int) (r Result) {
+	r.Input = n
+	defer func() {
+		if v := recover(); v != nil {
+			r = Result{Input: n, Err: fmt.Errorf("%w: input %d: %v", ErrPanic, n, v)}
+		}
+	}()
+
+	t, err := Analyze(big.NewInt(int64(n)))
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Steps, r.StoppingTime, r.Peak = t.Steps, t.StoppingTime, t.Peak
+	return r
+}
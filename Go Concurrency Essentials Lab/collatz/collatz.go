@@ -0,0 +1,147 @@
+// Go Concurrency Essentials - Collatz Computation
+// Description: Collatz step counting that reports problems as errors
+//              instead of panicking inside worker goroutines
+
+package collatz
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Errors reported by Steps (wrapped with the offending input)
+var (
+	ErrNonPositive  = errors.New("collatz: nonpositive input")
+	ErrOverflow     = errors.New("collatz: overflow")
+	ErrTooManySteps = errors.New("collatz: too many steps")
+	ErrPanic        = errors.New("collatz: worker panicked")
+)
+
+// maxInt is the largest value an int can hold
+const maxInt = int(^uint(0) >> 1)
+
+// Steps computes the number of steps to reach 1 under the Collatz conjecture
+// Collatz conjecture: For any positive integer n:
+// - If n is even: n → n/2
+// - If n is odd: n → 3n+1
+// Repeat until reaching 1
+//
+// Parameters:
+//   - n: Starting number (must be positive)
+//
+// Returns:
+//   - Number of steps to reach 1
+//   - ErrNonPositive, ErrOverflow or ErrTooManySteps (test with errors.Is)
+//
+// Reference: https://en.wikipedia.org/wiki/Collatz_conjecture
+func Steps(n int) (steps int, err error) {
+	steps, _, _, err = trajectory(n)
+	return steps, err
+}
+
+// trajectory follows n down to 1 in int arithmetic
+// Returns:
+//   - Steps to reach 1, stopping time (first step below n) and peak value
+func trajectory(n int) (steps, stopping, peak int, err error) {
+	if n <= 0 {
+		return 0, 0, 0, fmt.Errorf("%w: %d", ErrNonPositive, n)
+	}
+	start := n
+	peak = n
+
+	for ; n > 1; steps++ {
+		// Check for overflow (too many steps)
+		if steps < 0 {
+			return 0, 0, 0, fmt.Errorf("%w: starting from %d", ErrTooManySteps, start)
+		}
+		if stopping == 0 && n < start {
+			stopping = steps
+		}
+
+		if n%2 == 0 {
+			// Even: divide by 2
+			n /= 2
+			continue
+		}
+
+		// Odd: multiply by 3 and add 1
+		// Check for integer overflow before computing
+		if n > (maxInt-1)/3 {
+			return 0, 0, 0, fmt.Errorf("%w: 3*%d+1 starting from %d", ErrOverflow, n, start)
+		}
+		n = 3*n + 1
+		peak = max(peak, n)
+	}
+	if stopping == 0 && n < start {
+		stopping = steps // Dropped below start on the final step
+	}
+
+	return steps, stopping, peak, nil
+}
+
+// ==================== PER-INPUT RESULTS ====================
+// Result is the outcome for one input
+type Result struct {
+	Input        int      // Starting number
+	Steps        int      // Steps to reach 1 (valid when Err is nil)
+	StoppingTime int      // Steps until the value first drops below Input
+	Peak         *big.Int // Largest value reached (nil when Err is set)
+	Err          error    // Why the computation failed, if it did
+}
+
+// Compute runs Steps for one input, recovering any panic into Err
+// so a single bad input cannot take down the other workers
+func Compute(n int) (r Result) {
+	r.Input = n
+	defer func() {
+		if v := recover(); v != nil {
+			r.Steps = 0
+			r.Err = fmt.Errorf("%w: input %d: %v", ErrPanic, n, v)
+		}
+	}()
+	steps, stopping, peak, err := trajectory(n)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Steps, r.StoppingTime, r.Peak = steps, stopping, big.NewInt(int64(peak))
+	return r
+}
+
+// Results holds one Result per input
+type Results []Result
+
+// Succeeded returns the results that completed without error
+func (rs Results) Succeeded() Results {
+	var out Results
+	for _, r := range rs {
+		if r.Err == nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Failed returns the results that reported an error
+func (rs Results) Failed() Results {
+	var out Results
+	for _, r := range rs {
+		if r.Err != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Steps returns the step counts in input order, with -1 for failed inputs
+func (rs Results) Steps() []int {
+	out := make([]int, len(rs))
+	for i, r := range rs {
+		out[i] = r.Steps
+		if r.Err != nil {
+			out[i] = -1
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+// Go Concurrency Essentials - Memoized Collatz
+// Description: Concurrent cache of known step counts and peaks
+//              A trajectory stops walking as soon as it reaches a value
+//              whose result is already cached
+
+package collatz
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Memo caches the steps and peak of every n below its size
+// Entries are written once with atomic stores and read with atomic loads,
+// so any number of workers can share one Memo without locking. Two
+// workers computing the same n store identical values, so races between
+// writers are harmless
+type Memo struct {
+	steps []atomic.Uint32 // steps+1 for n, 0 meaning "not cached yet"
+	peaks []atomic.Uint64 // Peak value reached from n
+}
+
+// NewMemo creates a cache for 1 <= n < size
+// Memory use is 12 bytes per entry
+func NewMemo(size int) *Memo {
+	m := &Memo{
+		steps: make([]atomic.Uint32, size),
+		peaks: make([]atomic.Uint64, size),
+	}
+	if size > 1 {
+		m.steps[1].Store(1) // 1 takes 0 steps
+		m.peaks[1].Store(1)
+	}
+	return m
+}
+
+// lookup returns the cached result for n, if any
+func (m *Memo) lookup(n uint64) (steps int, peak uint64, ok bool) {
+	if n >= uint64(len(m.steps)) {
+		return 0, 0, false
+	}
+	s := m.steps[n].Load()
+	if s == 0 {
+		return 0, 0, false
+	}
+	return int(s - 1), m.peaks[n].Load(), true
+}
+
+// Trajectory computes steps and peak for n, using and filling the cache
+// Parameters:
+//   - n: Starting number (must be positive)
+//
+// Returns:
+//   - Steps to reach 1 and the largest value reached
+//   - ErrNonPositive for n == 0, ErrOverflow if a value exceeds 64 bits
+func (m *Memo) Trajectory(n uint64) (steps int, peak uint64, err error) {
+	if n == 0 {
+		return 0, 0, fmt.Errorf("%w: 0", ErrNonPositive)
+	}
+	if s, p, ok := m.lookup(n); ok {
+		return s, p, nil
+	}
+
+	// Walk until the trajectory reaches a cached value
+	v, peak := n, n
+	for {
+		if v%2 == 0 {
+			v /= 2
+		} else {
+			if v > limit64 {
+				return 0, 0, fmt.Errorf("%w: 3*%d+1 starting from %d", ErrOverflow, v, n)
+			}
+			v = 3*v + 1
+			peak = max(peak, v)
+		}
+		steps++
+
+		if v == 1 {
+			break
+		}
+		if s, p, ok := m.lookup(v); ok {
+			steps += s
+			peak = max(peak, p)
+			break
+		}
+	}
+
+	// Publish peak before steps: readers check steps first
+	if n < uint64(len(m.steps)) {
+		m.peaks[n].Store(peak)
+		m.steps[n].Store(uint32(steps + 1))
+	}
+	return steps, peak, nil
+}
+
+// Warm fills the cache for every n below upTo (capped at the cache size)
+// A resumed search starts far above the cache range, so without warming
+// its trajectories would never find cached values
+func (m *Memo) Warm(upTo uint64) error {
+	for n := uint64(1); n < min(upTo, uint64(len(m.steps))); n++ {
+		if _, _, err := m.Trajectory(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Go Concurrency Essentials - Concurrent Counters
+// Description: Four ways to count from many goroutines behind one interface,
+//              from the mutex and atomic counters of mutex.go and atomic.go
+//              to a sharded counter and one owned by a single goroutine
+//
+// Trade-offs:
+// - Mutex: simplest, but every increment serialises on one lock
+// - Atomic: lock-free, but every core still fights over one cache line
+// - Sharded: increments spread over padded stripes (one per P), so they
+//   rarely collide; reading has to sum every stripe
+// - Channel: one owner goroutine holds the count; every increment is a
+//   channel send, which is the slowest but needs no shared memory at all
+
+package counter
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"essentials/padded"
+)
+
+// Counter is a concurrency-safe counter
+type Counter interface {
+	Inc()         // Add one
+	Value() int64 // Current total
+	Close()       // Release resources (the channel counter's goroutine)
+	Name() string // Implementation name for reports
+}
+
+// ==================== MUTEX ====================
+// Mutex guards a plain int64 with sync.Mutex
+type Mutex struct {
+	mutex sync.Mutex
+	n     int64
+}
+
+// NewMutex creates a mutex counter
+func NewMutex() *Mutex { return &Mutex{} }
+
+func (c *Mutex) Inc() {
+	c.mutex.Lock()
+	c.n++
+	c.mutex.Unlock()
+}
+
+func (c *Mutex) Value() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.n
+}
+
+func (c *Mutex) Close()       {}
+func (c *Mutex) Name() string { return "mutex" }
+
+// ==================== ATOMIC ====================
+// Atomic is a single atomic.Int64
+type Atomic struct {
+	n atomic.Int64
+}
+
+// NewAtomic creates an atomic counter
+func NewAtomic() *Atomic { return &Atomic{} }
+
+func (c *Atomic) Inc()         { c.n.Add(1) }
+func (c *Atomic) Value() int64 { return c.n.Load() }
+func (c *Atomic) Close()       {}
+func (c *Atomic) Name() string { return "atomic" }
+
+// ==================== SHARDED ====================
+// Sharded spreads increments over one padded stripe per P
+// Go does not expose which P a goroutine runs on, so each increment picks
+// a stripe with the runtime's per-thread random source; with as many
+// stripes as Ps (rounded up to a power of two), collisions are rare
+type Sharded struct {
+	stripes []padded.Int64 // Padded so two stripes never share a cache line
+	mask    uint32
+}
+
+// NewSharded creates a sharded counter sized for the current GOMAXPROCS
+func NewSharded() *Sharded {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n *= 2
+	}
+	return &Sharded{stripes: make([]padded.Int64, n), mask: uint32(n - 1)}
+}
+
+func (c *Sharded) Inc() {
+	c.stripes[rand.Uint32()&c.mask].Add(1)
+}
+
+// Value sums the stripes; concurrent increments may or may not be counted
+func (c *Sharded) Value() int64 {
+	var sum int64
+	for i := range c.stripes {
+		sum += c.stripes[i].Load()
+	}
+	return sum
+}
+
+func (c *Sharded) Close()       {}
+func (c *Sharded) Name() string { return "sharded" }
+
+// ==================== CHANNEL-OWNED ====================
+// Channel keeps the count in a single owner goroutine; other goroutines
+// only send it messages ("share memory by communicating")
+type Channel struct {
+	incs  chan struct{}   // One message per increment
+	reads chan chan int64 // Value requests
+	done  chan struct{}   // Closed by Close to stop the owner
+	wg    sync.WaitGroup
+}
+
+// NewChannel starts the owner goroutine; call Close to stop it
+func NewChannel() *Channel {
+	c := &Channel{
+		incs:  make(chan struct{}, 1024),
+		reads: make(chan chan int64),
+		done:  make(chan struct{}),
+	}
+	c.wg.Go(c.own)
+	return c
+}
+
+// own is the owner goroutine: the only code that touches n
+func (c *Channel) own() {
+	var n int64
+	for {
+		select {
+		case <-c.incs:
+			n++
+		case reply := <-c.reads:
+			// Apply increments already queued so a caller's own earlier
+			// Inc calls are always included
+			for len(c.incs) > 0 {
+				<-c.incs
+				n++
+			}
+			reply <- n
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *Channel) Inc() { c.incs <- struct{}{} }
+
+func (c *Channel) Value() int64 {
+	reply := make(chan int64)
+	c.reads <- reply
+	return <-reply
+}
+
+// Close stops the owner goroutine; the counter must not be used afterwards
+func (c *Channel) Close() {
+	close(c.done)
+	c.wg.Wait()
+}
+
+func (c *Channel) Name() string { return "channel" }
+
+// ==================== REGISTRY ====================
+// Constructors lists every implementation, in report order
+var Constructors = []func() Counter{
+	func() Counter { return NewMutex() },
+	func() Counter { return NewAtomic() },
+	func() Counter { return NewSharded() },
+	func() Counter { return NewChannel() },
+}
@@ -0,0 +1,190 @@
+// Go Concurrency Essentials - Counter Benchmark
+// Description: Measures the four counter.Counter implementations over a
+//              sweep of goroutine counts, increments and GOMAXPROCS values
+//
+// Writes one CSV row per run and prints a summary table of ns/op per
+// goroutine count, plus how throughput scales from the fewest goroutines
+// to the most. Every run checks that no increment was lost
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"essentials/counter"
+	"essentials/flagutil"
+)
+
+// run is one measured configuration
+type run struct {
+	impl       string
+	procs      int // GOMAXPROCS
+	goroutines int
+	increments int // Per goroutine
+	elapsed    time.Duration
+	speedup    float64 // Throughput relative to the fewest goroutines
+}
+
+// ops returns the total number of increments
+func (r run) ops() int { return r.goroutines * r.increments }
+
+// nsPerOp returns the wall time per increment
+func (r run) nsPerOp() float64 { return float64(r.elapsed.Nanoseconds()) / float64(r.ops()) }
+
+// opsPerSec returns the throughput
+func (r run) opsPerSec() float64 { return float64(r.ops()) / r.elapsed.Seconds() }
+
+// measure runs goroutines x increments on a fresh counter and returns the
+// best elapsed time of reps attempts
+// Returns:
+//   - Best elapsed time, or an error if the final value is wrong
+func measure(newCounter func() counter.Counter, goroutines, increments, reps int) (time.Duration, error) {
+	best := time.Duration(1<<63 - 1)
+	for range reps {
+		c := newCounter()
+		var wg sync.WaitGroup
+		start := time.Now()
+		for range goroutines {
+			wg.Go(func() {
+				for range increments {
+					c.Inc()
+				}
+			})
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+
+		got, want := c.Value(), int64(goroutines*increments)
+		c.Close()
+		if got != want {
+			return 0, fmt.Errorf("%s: got %d, want %d", c.Name(), got, want)
+		}
+		best = min(best, elapsed)
+	}
+	return best, nil
+}
+
+// nameOf returns an implementation's name without leaking its resources
+func nameOf(newCounter func() counter.Counter) string {
+	c := newCounter()
+	defer c.Close()
+	return c.Name()
+}
+
+// writeCSV writes every run to path
+func writeCSV(path string, runs []run) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"impl", "gomaxprocs", "goroutines", "increments", "total_ops",
+		"elapsed_ns", "ns_per_op", "ops_per_sec", "speedup"})
+	for _, r := range runs {
+		w.Write([]string{
+			r.impl,
+			strconv.Itoa(r.procs),
+			strconv.Itoa(r.goroutines),
+			strconv.Itoa(r.increments),
+			strconv.Itoa(r.ops()),
+			strconv.FormatInt(r.elapsed.Nanoseconds(), 10),
+			strconv.FormatFloat(r.nsPerOp(), 'f', 2, 64),
+			strconv.FormatFloat(r.opsPerSec(), 'f', 0, 64),
+			strconv.FormatFloat(r.speedup, 'f', 2, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// main runs the sweep, writes the CSV and prints the summary
+func main() {
+	goroutinesFlag := flag.String("goroutines", "1,2,4,8,16", "goroutine counts to sweep")
+	incrementsFlag := flag.String("increments", "1000,100000", "increments per goroutine to sweep")
+	procsFlag := flag.String("procs", strconv.Itoa(runtime.NumCPU()), "GOMAXPROCS values to sweep")
+	reps := flag.Int("reps", 3, "runs per configuration (best is kept)")
+	csvPath := flag.String("csv", "counterbench.csv", "CSV output file (empty to skip)")
+	flag.Parse()
+
+	goroutines, err := flagutil.ParseInts(*goroutinesFlag)
+	if err != nil {
+		log.Fatal("bad -goroutines: ", err)
+	}
+	increments, err := flagutil.ParseInts(*incrementsFlag)
+	if err != nil {
+		log.Fatal("bad -increments: ", err)
+	}
+	procs, err := flagutil.ParseInts(*procsFlag)
+	if err != nil {
+		log.Fatal("bad -procs: ", err)
+	}
+
+	// ==================== SWEEP ====================
+	var runs []run
+	for _, p := range procs {
+		runtime.GOMAXPROCS(p) // Before building counters: sharded sizes itself from it
+		for _, inc := range increments {
+			for _, newCounter := range counter.Constructors {
+				var base float64 // Throughput at the first goroutine count
+				for i, g := range goroutines {
+					elapsed, err := measure(newCounter, g, inc, *reps)
+					if err != nil {
+						log.Fatal("lost increments: ", err)
+					}
+					r := run{impl: nameOf(newCounter), procs: p, goroutines: g, increments: inc, elapsed: elapsed}
+					if i == 0 {
+						base = r.opsPerSec()
+					}
+					r.speedup = r.opsPerSec() / base
+					runs = append(runs, r)
+				}
+			}
+		}
+	}
+	// ===============================================
+
+	if *csvPath != "" {
+		if err := writeCSV(*csvPath, runs); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d runs written to %s\n", len(runs), *csvPath)
+	}
+
+	// ==================== SUMMARY ====================
+	// One table per GOMAXPROCS, for the largest increment count
+	inc := increments[len(increments)-1]
+	for _, p := range procs {
+		fmt.Printf("\nGOMAXPROCS=%d, %d increments per goroutine (ns/op)\n", p, inc)
+		fmt.Printf("%-8s", "counter")
+		for _, g := range goroutines {
+			fmt.Printf(" %8s", fmt.Sprintf("g=%d", g))
+		}
+		fmt.Printf(" %9s\n", "scaling")
+
+		for _, newCounter := range counter.Constructors {
+			name := nameOf(newCounter)
+			fmt.Printf("%-8s", name)
+			var last run
+			for _, r := range runs {
+				if r.impl == name && r.procs == p && r.increments == inc {
+					fmt.Printf(" %8.1f", r.nsPerOp())
+					last = r
+				}
+			}
+			fmt.Printf(" %8.2fx\n", last.speedup)
+		}
+	}
+	// =================================================
+}
@@ -0,0 +1,105 @@
+// Go Concurrency Essentials - False Sharing Experiment
+// Description: Every goroutine increments its own counter, so there is no
+//              logical sharing at all. With the counters packed next to each
+//              other in one slice they still share cache lines; with
+//              padded.Int64 each has a line to itself. The difference in
+//              throughput is the cost of false sharing
+//
+// The effect needs real parallelism: with GOMAXPROCS=1 both layouts run
+// at the same speed. Exits 1 if any counter loses increments
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"essentials/padded"
+)
+
+// incrementer is the per-goroutine counter interface both layouts satisfy
+type incrementer interface {
+	Add(delta int64) int64
+	Load() int64
+}
+
+// measure runs one goroutine per counter, each adding 1 increments times
+// Returns:
+//   - Best elapsed time of reps runs
+func measure(counters func() []incrementer, increments, reps int) time.Duration {
+	best := time.Duration(1<<63 - 1)
+	for range reps {
+		cs := counters()
+		var wg sync.WaitGroup
+		start := time.Now()
+		for _, c := range cs {
+			wg.Go(func() {
+				for range increments {
+					c.Add(1)
+				}
+			})
+		}
+		wg.Wait()
+		best = min(best, time.Since(start))
+		for i, c := range cs {
+			if n := c.Load(); n != int64(increments) {
+				fmt.Printf("FAIL: counter %d is %d, want %d (lost increments)\n", i, n, increments)
+				os.Exit(1)
+			}
+		}
+	}
+	return best
+}
+
+// main runs the packed and padded layouts and compares them
+func main() {
+	goroutines := flag.Int("goroutines", max(runtime.GOMAXPROCS(0), 2), "goroutines (one counter each)")
+	increments := flag.Int("increments", 5_000_000, "increments per goroutine")
+	reps := flag.Int("reps", 3, "runs per layout (best is kept)")
+	flag.Parse()
+
+	// ==================== LAYOUTS ====================
+	// Packed: counters are 8 bytes apart, so 8 share each 64-byte line
+	packed := func() []incrementer {
+		backing := make([]atomic.Int64, *goroutines)
+		out := make([]incrementer, len(backing))
+		for i := range backing {
+			out[i] = &backing[i]
+		}
+		return out
+	}
+	// Padded: counters are a full line apart
+	spaced := func() []incrementer {
+		backing := make([]padded.Int64, *goroutines)
+		out := make([]incrementer, len(backing))
+		for i := range backing {
+			out[i] = &backing[i]
+		}
+		return out
+	}
+	// =================================================
+
+	fmt.Printf("%d goroutines x %d increments, GOMAXPROCS=%d\n",
+		*goroutines, *increments, runtime.GOMAXPROCS(0))
+	if runtime.GOMAXPROCS(0) == 1 {
+		fmt.Println("note: GOMAXPROCS=1, goroutines never run in parallel, so no false sharing is expected")
+	}
+	fmt.Println()
+
+	total := float64(*goroutines * *increments)
+	tPacked := measure(packed, *increments, *reps)
+	tPadded := measure(spaced, *increments, *reps)
+
+	fmt.Printf("%-7s %11s %10s %14s\n", "layout", "stride", "elapsed", "increments/s")
+	fmt.Printf("%-7s %8d B %10v %14.0f\n", "packed", unsafe.Sizeof(atomic.Int64{}),
+		tPacked.Round(time.Millisecond), total/tPacked.Seconds())
+	fmt.Printf("%-7s %8d B %10v %14.0f\n", "padded", unsafe.Sizeof(padded.Int64{}),
+		tPadded.Round(time.Millisecond), total/tPadded.Seconds())
+	fmt.Printf("\npadded is %.2fx the throughput of packed\n", tPacked.Seconds()/tPadded.Seconds())
+}
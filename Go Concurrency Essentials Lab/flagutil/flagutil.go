@@ -0,0 +1,28 @@
+// Go Concurrency Essentials - Flag Helpers
+// Description: Parsing for benchmark sweep flags, which take comma
+//              separated lists such as -goroutines 1,4,16
+
+package flagutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseInts parses a comma separated list of integers
+// Parameters:
+//   - s: List such as "1, 4,16" (spaces around fields are ignored)
+//
+// Returns:
+//   - The integers in order, or the first field's parse error
+func ParseInts(s string) ([]int, error) {
+	var out []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -1,4 +1,4 @@
-module sem-ex
+module essentials
 
 go 1.25.3
 
@@ -0,0 +1,416 @@
+// Go Concurrency Essentials - Instrumented Mutex
+// Description: Drop-in sync.Mutex and sync.RWMutex wrappers that record,
+//              per lock and per call site (via runtime.Callers):
+//              - acquisitions and how many had to wait
+//              - total and longest wait time
+//              - total and longest hold time
+//              and keep the longest individual holds
+//
+// A lock without a Profiler (including the zero value) is a plain mutex
+// plus one nil check, so profiling can be left in the code and switched on
+// by passing a Profiler. Reports are ranked by total wait and can be
+// printed as a table or exported as JSON
+
+package instrument
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// longestHolds is how many individual holds a Profiler keeps
+const longestHolds = 5
+
+// ==================== PROFILER ====================
+// siteKey identifies one call site of one lock
+type siteKey struct {
+	lock string
+	mode string // "write" or "read"
+	pc   uintptr
+}
+
+// siteStats accumulates the measurements for one siteKey
+type siteStats struct {
+	acquisitions, contended, holds int64
+	waitTotal, waitMax             time.Duration
+	holdTotal, holdMax             time.Duration
+}
+
+// heldAt is one long hold, resolved to a Hold when reported
+type heldAt struct {
+	key  siteKey
+	hold time.Duration
+}
+
+// Profiler collects lock measurements from every Mutex and RWMutex that
+// uses it. Recording takes the Profiler's own mutex, so a profiled lock
+// costs a little more than a plain one
+type Profiler struct {
+	mutex   sync.Mutex
+	sites   map[siteKey]*siteStats
+	longest []heldAt // Sorted, longest first
+}
+
+// NewProfiler creates an empty Profiler
+func NewProfiler() *Profiler {
+	return &Profiler{sites: make(map[siteKey]*siteStats)}
+}
+
+// caller returns the pc of the function that called Lock or RLock (or
+// RLocker's Lock)
+func caller() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // Skip Callers, caller and the Lock method
+	return pcs[0]
+}
+
+// acquired records one acquisition at key
+func (p *Profiler) acquired(key siteKey, wait time.Duration, contended bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	s := p.site(key)
+	s.acquisitions++
+	if contended {
+		s.contended++
+	}
+	s.waitTotal += wait
+	s.waitMax = max(s.waitMax, wait)
+}
+
+// held records one hold of the lock, attributed to key
+func (p *Profiler) held(key siteKey, hold time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	s := p.site(key)
+	s.holds++
+	s.holdTotal += hold
+	s.holdMax = max(s.holdMax, hold)
+
+	if len(p.longest) < longestHolds || hold > p.longest[len(p.longest)-1].hold {
+		i, _ := slices.BinarySearchFunc(p.longest, hold, func(h heldAt, d time.Duration) int {
+			return cmp.Compare(d, h.hold) // Descending
+		})
+		p.longest = slices.Insert(p.longest, i, heldAt{key, hold})
+		p.longest = p.longest[:min(len(p.longest), longestHolds)]
+	}
+}
+
+// site returns the stats for key, creating them on first use
+// (caller holds p.mutex)
+func (p *Profiler) site(key siteKey) *siteStats {
+	s, ok := p.sites[key]
+	if !ok {
+		s = &siteStats{}
+		p.sites[key] = s
+	}
+	return s
+}
+
+// Reset clears every measurement
+func (p *Profiler) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	clear(p.sites)
+	p.longest = nil
+}
+
+// ==================================================
+
+// ==================== MUTEX ====================
+// Mutex is a sync.Mutex that reports to a Profiler. The zero value is an
+// unlocked, unprofiled mutex
+type Mutex struct {
+	mutex sync.Mutex
+	name  string
+	prof  *Profiler
+
+	// Set by Lock and read by Unlock, so guarded by mutex itself
+	site  siteKey
+	since time.Time
+}
+
+// NewMutex creates a mutex called name that reports to p (nil disables
+// profiling)
+func NewMutex(name string, p *Profiler) *Mutex {
+	return &Mutex{name: name, prof: p}
+}
+
+// Lock acquires the mutex, recording the wait against the caller
+func (m *Mutex) Lock() {
+	if m.prof == nil {
+		m.mutex.Lock()
+		return
+	}
+	key := siteKey{lock: m.name, mode: "write", pc: caller()}
+	wait, contended := lockTimed(&m.mutex)
+	m.site, m.since = key, time.Now()
+	m.prof.acquired(key, wait, contended)
+}
+
+// Unlock releases the mutex, recording how long it was held
+func (m *Mutex) Unlock() {
+	if m.prof == nil {
+		m.mutex.Unlock()
+		return
+	}
+	key, hold := m.site, time.Since(m.since)
+	m.mutex.Unlock()
+	m.prof.held(key, hold)
+}
+
+// lockTimed acquires l, timing the wait only if it is not free
+// Returns:
+//   - How long Lock blocked, and whether it had to block at all
+func lockTimed(l interface {
+	TryLock() bool
+	Lock()
+}) (time.Duration, bool) {
+	if l.TryLock() {
+		return 0, false
+	}
+	start := time.Now()
+	l.Lock()
+	return time.Since(start), true
+}
+
+// ===============================================
+
+// ==================== RWMUTEX ====================
+// RWMutex is a sync.RWMutex that reports to a Profiler. Writers are
+// measured like Mutex. Readers overlap, so a read hold is the span from
+// the first reader in to the last reader out, attributed to the call site
+// of the reader that opened it. The zero value is an unprofiled RWMutex
+type RWMutex struct {
+	mutex sync.RWMutex
+	name  string
+	prof  *Profiler
+
+	// Writer hold, guarded by mutex held for writing
+	site  siteKey
+	since time.Time
+
+	// Read span, guarded by span
+	span      sync.Mutex
+	readers   int
+	spanSite  siteKey
+	spanSince time.Time
+}
+
+// NewRWMutex creates a readers-writer mutex called name that reports to p
+// (nil disables profiling)
+func NewRWMutex(name string, p *Profiler) *RWMutex {
+	return &RWMutex{name: name, prof: p}
+}
+
+// Lock acquires the mutex for writing
+func (m *RWMutex) Lock() {
+	if m.prof == nil {
+		m.mutex.Lock()
+		return
+	}
+	key := siteKey{lock: m.name, mode: "write", pc: caller()}
+	wait, contended := lockTimed(&m.mutex)
+	m.site, m.since = key, time.Now()
+	m.prof.acquired(key, wait, contended)
+}
+
+// Unlock releases a write lock
+func (m *RWMutex) Unlock() {
+	if m.prof == nil {
+		m.mutex.Unlock()
+		return
+	}
+	key, hold := m.site, time.Since(m.since)
+	m.mutex.Unlock()
+	m.prof.held(key, hold)
+}
+
+// RLock acquires the mutex for reading
+func (m *RWMutex) RLock() {
+	if m.prof == nil {
+		m.mutex.RLock()
+		return
+	}
+	m.rlock(caller())
+}
+
+// rlock acquires the mutex for reading, recording the wait against pc
+func (m *RWMutex) rlock(pc uintptr) {
+	key := siteKey{lock: m.name, mode: "read", pc: pc}
+	var wait time.Duration
+	contended := !m.mutex.TryRLock()
+	if contended {
+		start := time.Now()
+		m.mutex.RLock()
+		wait = time.Since(start)
+	}
+	m.prof.acquired(key, wait, contended)
+
+	m.span.Lock()
+	m.readers++
+	if m.readers == 1 {
+		m.spanSite, m.spanSince = key, time.Now()
+	}
+	m.span.Unlock()
+}
+
+// RUnlock releases a read lock
+func (m *RWMutex) RUnlock() {
+	if m.prof == nil {
+		m.mutex.RUnlock()
+		return
+	}
+	m.span.Lock()
+	m.readers--
+	last := m.readers == 0
+	key, hold := m.spanSite, time.Since(m.spanSince)
+	m.span.Unlock()
+
+	m.mutex.RUnlock()
+	if last {
+		m.prof.held(key, hold)
+	}
+}
+
+// RLocker returns a sync.Locker that read-locks m
+func (m *RWMutex) RLocker() sync.Locker { return rlocker{m} }
+
+type rlocker struct{ m *RWMutex }
+
+// Lock read-locks m, recording the wait against the caller of Lock rather
+// than against this wrapper
+func (r rlocker) Lock() {
+	if r.m.prof == nil {
+		r.m.mutex.RLock()
+		return
+	}
+	r.m.rlock(caller())
+}
+
+func (r rlocker) Unlock() { r.m.RUnlock() }
+
+// =================================================
+
+// ==================== REPORT ====================
+// SiteStats is the contention at one call site of one lock
+type SiteStats struct {
+	Lock         string        `json:"lock"`
+	Mode         string        `json:"mode"` // "write" or "read"
+	Site         string        `json:"site"` // Function and file:line of the caller
+	Acquisitions int64         `json:"acquisitions"`
+	Contended    int64         `json:"contended"` // Acquisitions that had to wait
+	WaitTotal    time.Duration `json:"wait_total_ns"`
+	WaitMax      time.Duration `json:"wait_max_ns"`
+	Holds        int64         `json:"holds"` // Read spans count once however many readers overlap
+	HoldTotal    time.Duration `json:"hold_total_ns"`
+	HoldMax      time.Duration `json:"hold_max_ns"`
+}
+
+// MeanWait returns the average wait per acquisition
+func (s SiteStats) MeanWait() time.Duration {
+	if s.Acquisitions == 0 {
+		return 0
+	}
+	return s.WaitTotal / time.Duration(s.Acquisitions)
+}
+
+// MeanHold returns the average hold time
+func (s SiteStats) MeanHold() time.Duration {
+	if s.Holds == 0 {
+		return 0
+	}
+	return s.HoldTotal / time.Duration(s.Holds)
+}
+
+// Hold is one of the longest individual holds
+type Hold struct {
+	Lock string        `json:"lock"`
+	Mode string        `json:"mode"`
+	Site string        `json:"site"`
+	Hold time.Duration `json:"hold_ns"`
+}
+
+// Report is a point-in-time copy of a Profiler's measurements
+type Report struct {
+	Sites   []SiteStats `json:"sites"`   // Most total wait first
+	Longest []Hold      `json:"longest"` // Longest holds first
+}
+
+// describe resolves pc to "function (file:line)"
+func describe(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s:%d)", frame.Function, filepath.Base(frame.File), frame.Line)
+}
+
+// Report returns the measurements ranked by total wait, then total hold
+func (p *Profiler) Report() Report {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var r Report
+	for key, s := range p.sites {
+		r.Sites = append(r.Sites, SiteStats{
+			Lock:         key.lock,
+			Mode:         key.mode,
+			Site:         describe(key.pc),
+			Acquisitions: s.acquisitions,
+			Contended:    s.contended,
+			WaitTotal:    s.waitTotal,
+			WaitMax:      s.waitMax,
+			Holds:        s.holds,
+			HoldTotal:    s.holdTotal,
+			HoldMax:      s.holdMax,
+		})
+	}
+	slices.SortFunc(r.Sites, func(a, b SiteStats) int {
+		return cmp.Or(
+			cmp.Compare(b.WaitTotal, a.WaitTotal),
+			cmp.Compare(b.HoldTotal, a.HoldTotal),
+			cmp.Compare(a.Site, b.Site),
+		)
+	})
+	for _, h := range p.longest {
+		r.Longest = append(r.Longest, Hold{Lock: h.key.lock, Mode: h.key.mode, Site: describe(h.key.pc), Hold: h.hold})
+	}
+	return r
+}
+
+// WriteTable prints the report as a ranked, human-readable table
+func (r Report) WriteTable(w io.Writer) error {
+	var b strings.Builder // Built in full, then written once
+	fmt.Fprintf(&b, "%-4s %-10s %-5s %8s %9s %11s %11s %11s %11s  %s\n",
+		"rank", "lock", "mode", "acquired", "contended", "wait total", "wait max", "hold mean", "hold max", "site")
+	const tenth = 100 * time.Nanosecond // Display precision
+	for i, s := range r.Sites {
+		fmt.Fprintf(&b, "%-4d %-10s %-5s %8d %9d %11v %11v %11v %11v  %s\n",
+			i+1, s.Lock, s.Mode, s.Acquisitions, s.Contended,
+			s.WaitTotal.Round(tenth), s.WaitMax.Round(tenth),
+			s.MeanHold().Round(tenth), s.HoldMax.Round(tenth), s.Site)
+	}
+	fmt.Fprintln(&b, "\nlongest holds")
+	for _, h := range r.Longest {
+		fmt.Fprintf(&b, "  %11v  %-10s %-5s %s\n", h.Hold.Round(tenth), h.Lock, h.Mode, h.Site)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the report as indented JSON (durations in nanoseconds)
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// ================================================
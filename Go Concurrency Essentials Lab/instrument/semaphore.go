@@ -0,0 +1,296 @@
+// Go Concurrency Essentials - Instrumented Semaphore
+// Description: Wraps any semaphore and records how it is contended:
+//              acquire wait-time histogram, current and peak holders,
+//              total acquisitions, timeouts and cancellations
+//
+// Metrics can be printed as a text table or in Prometheus exposition
+// format, so pool sizes can be chosen from data
+
+package instrument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// ==================== SEMAPHORES ====================
+// Semaphore is the single-slot acquire/release interface being measured
+// (it matches pool.Limiter, so an Instrumented can drive a pool)
+type Semaphore interface {
+	Acquire(ctx context.Context) error
+	Release()
+}
+
+// chanSemaphore is the buffered-channel semaphore from semaphore.go
+type chanSemaphore chan struct{}
+
+// Channel returns a buffered-channel semaphore with n slots
+func Channel(n int) Semaphore {
+	return make(chanSemaphore, n)
+}
+
+func (c chanSemaphore) Acquire(ctx context.Context) error {
+	select {
+	case c <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c chanSemaphore) Release() { <-c }
+
+// weightedSemaphore adapts semaphore.Weighted, one token per Acquire
+type weightedSemaphore struct {
+	w *semaphore.Weighted
+}
+
+// Weighted adapts a golang.org/x/sync semaphore to Semaphore
+func Weighted(w *semaphore.Weighted) Semaphore {
+	return weightedSemaphore{w: w}
+}
+
+func (s weightedSemaphore) Acquire(ctx context.Context) error { return s.w.Acquire(ctx, 1) }
+func (s weightedSemaphore) Release()                          { s.w.Release(1) }
+
+// ====================================================
+
+// Buckets are the histogram upper bounds for acquire wait time
+// Each bucket counts waits <= its bound; the last bucket is +Inf
+var Buckets = []time.Duration{
+	time.Microsecond,
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// ==================== INSTRUMENTED ====================
+// Instrumented records metrics for every Acquire and Release
+type Instrumented struct {
+	name string
+	sem  Semaphore
+
+	buckets       []atomic.Uint64 // Non-cumulative counts, len(Buckets)+1
+	waitSum       atomic.Int64    // Total wait of successful acquires (ns)
+	waitMax       atomic.Int64    // Longest successful wait (ns)
+	acquisitions  atomic.Uint64   // Successful acquires
+	timeouts      atomic.Uint64   // Acquires that hit a context deadline
+	cancellations atomic.Uint64   // Acquires whose context was cancelled
+	holders       atomic.Int64    // Current holders
+	peak          atomic.Int64    // Most holders at once
+}
+
+// Wrap instruments sem under the given name (used as a metric label)
+func Wrap(name string, sem Semaphore) *Instrumented {
+	return &Instrumented{
+		name:    name,
+		sem:     sem,
+		buckets: make([]atomic.Uint64, len(Buckets)+1),
+	}
+}
+
+// Acquire waits for a slot, recording how long it waited and why it failed
+func (s *Instrumented) Acquire(ctx context.Context) error {
+	start := time.Now()
+	err := s.sem.Acquire(ctx)
+	wait := time.Since(start)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		s.timeouts.Add(1)
+		return err
+	case err != nil:
+		s.cancellations.Add(1)
+		return err
+	}
+
+	s.acquisitions.Add(1)
+	s.observe(wait)
+	holders := s.holders.Add(1)
+	for {
+		peak := s.peak.Load()
+		if holders <= peak || s.peak.CompareAndSwap(peak, holders) {
+			break
+		}
+	}
+	return nil
+}
+
+// AcquireTimeout is Acquire with a deadline of d
+func (s *Instrumented) AcquireTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return s.Acquire(ctx)
+}
+
+// Release returns a slot
+func (s *Instrumented) Release() {
+	s.holders.Add(-1)
+	s.sem.Release()
+}
+
+// observe adds one successful wait to the histogram
+func (s *Instrumented) observe(wait time.Duration) {
+	i := 0
+	for i < len(Buckets) && wait > Buckets[i] {
+		i++
+	}
+	s.buckets[i].Add(1)
+	s.waitSum.Add(int64(wait))
+	for {
+		m := s.waitMax.Load()
+		if int64(wait) <= m || s.waitMax.CompareAndSwap(m, int64(wait)) {
+			break
+		}
+	}
+}
+
+// ======================================================
+
+// ==================== SNAPSHOT ====================
+// Stats is a point-in-time copy of an Instrumented's metrics
+type Stats struct {
+	Name          string
+	Buckets       []uint64 // Non-cumulative counts per Buckets entry, then +Inf
+	WaitSum       time.Duration
+	WaitMax       time.Duration
+	Acquisitions  uint64
+	Timeouts      uint64
+	Cancellations uint64
+	Holders       int64
+	PeakHolders   int64
+}
+
+// Stats returns a snapshot of the metrics
+func (s *Instrumented) Stats() Stats {
+	st := Stats{
+		Name:          s.name,
+		Buckets:       make([]uint64, len(s.buckets)),
+		WaitSum:       time.Duration(s.waitSum.Load()),
+		WaitMax:       time.Duration(s.waitMax.Load()),
+		Acquisitions:  s.acquisitions.Load(),
+		Timeouts:      s.timeouts.Load(),
+		Cancellations: s.cancellations.Load(),
+		Holders:       s.holders.Load(),
+		PeakHolders:   s.peak.Load(),
+	}
+	for i := range s.buckets {
+		st.Buckets[i] = s.buckets[i].Load()
+	}
+	return st
+}
+
+// MeanWait returns the average wait of successful acquires
+func (st Stats) MeanWait() time.Duration {
+	if st.Acquisitions == 0 {
+		return 0
+	}
+	return st.WaitSum / time.Duration(st.Acquisitions)
+}
+
+// ==================================================
+
+// ==================== OUTPUT ====================
+
+// WriteTable prints the metrics as a human-readable table
+// Returns:
+//   - Any error writing to w
+func (s *Instrumented) WriteTable(w io.Writer) error {
+	st := s.Stats()
+	var b strings.Builder // Built in full, then written once
+	fmt.Fprintf(&b, "semaphore %q\n", st.Name)
+	fmt.Fprintf(&b, "  acquisitions   %d\n", st.Acquisitions)
+	fmt.Fprintf(&b, "  timeouts       %d\n", st.Timeouts)
+	fmt.Fprintf(&b, "  cancellations  %d\n", st.Cancellations)
+	fmt.Fprintf(&b, "  holders        %d (peak %d)\n", st.Holders, st.PeakHolders)
+	fmt.Fprintf(&b, "  wait mean      %v (max %v)\n", st.MeanWait(), st.WaitMax)
+	fmt.Fprintf(&b, "  wait histogram\n")
+
+	var most uint64
+	for _, n := range st.Buckets {
+		most = max(most, n)
+	}
+	for i, n := range st.Buckets {
+		label := "+Inf"
+		if i < len(Buckets) {
+			label = "<= " + Buckets[i].String()
+		}
+		bar := 0
+		if most > 0 {
+			bar = int(40 * n / most)
+		}
+		fmt.Fprintf(&b, "    %-10s %8d %s\n", label, n, strings.Repeat("#", bar))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WritePrometheus prints the metrics in Prometheus text exposition format
+func (s *Instrumented) WritePrometheus(w io.Writer) error {
+	return WritePrometheus(w, s)
+}
+
+// WritePrometheus prints several semaphores in Prometheus text exposition
+// format, one metric family per name with a series per semaphore label
+// Returns:
+//   - Any error writing to w
+func WritePrometheus(w io.Writer, sems ...*Instrumented) error {
+	var b strings.Builder // Built in full, then written once
+	stats := make([]Stats, len(sems))
+	for i, s := range sems {
+		stats[i] = s.Stats()
+	}
+	label := func(st Stats) string { return fmt.Sprintf("semaphore=%q", st.Name) }
+
+	fmt.Fprintln(&b, "# HELP semaphore_acquire_wait_seconds Time spent waiting in Acquire.")
+	fmt.Fprintln(&b, "# TYPE semaphore_acquire_wait_seconds histogram")
+	for _, st := range stats {
+		var cumulative uint64
+		for i, n := range st.Buckets {
+			cumulative += n
+			le := "+Inf"
+			if i < len(Buckets) {
+				le = strconv.FormatFloat(Buckets[i].Seconds(), 'g', -1, 64)
+			}
+			fmt.Fprintf(&b, "semaphore_acquire_wait_seconds_bucket{%s,le=%q} %d\n", label(st), le, cumulative)
+		}
+		fmt.Fprintf(&b, "semaphore_acquire_wait_seconds_sum{%s} %g\n", label(st), st.WaitSum.Seconds())
+		fmt.Fprintf(&b, "semaphore_acquire_wait_seconds_count{%s} %d\n", label(st), cumulative)
+	}
+
+	families := []struct {
+		name, kind, help string
+		value            func(Stats) int64
+	}{
+		{"semaphore_acquisitions_total", "counter", "Successful Acquire calls.",
+			func(st Stats) int64 { return int64(st.Acquisitions) }},
+		{"semaphore_timeouts_total", "counter", "Acquire calls that hit their deadline.",
+			func(st Stats) int64 { return int64(st.Timeouts) }},
+		{"semaphore_cancellations_total", "counter", "Acquire calls whose context was cancelled.",
+			func(st Stats) int64 { return int64(st.Cancellations) }},
+		{"semaphore_holders", "gauge", "Current holders.",
+			func(st Stats) int64 { return st.Holders }},
+		{"semaphore_holders_peak", "gauge", "Most holders at once.",
+			func(st Stats) int64 { return st.PeakHolders }},
+	}
+	for _, f := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+		for _, st := range stats {
+			fmt.Fprintf(&b, "%s{%s} %d\n", f.name, label(st), f.value(st))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,94 @@
+// Go Concurrency Essentials - Adaptive (AIMD) Concurrency Limit
+// Description: Tunes a Limiter's capacity from observed task latency using
+//              additive increase / multiplicative decrease, the same rule
+//              TCP uses for its congestion window
+
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AIMDConfig configures an Adaptive limiter
+type AIMDConfig struct {
+	Initial int           // Starting limit
+	Min     int           // Limit never drops below this (at least 1)
+	Max     int           // Limit never rises above this
+	Target  time.Duration // Latency at or below which the limit grows
+	Backoff float64       // Multiplier applied on a slow task (e.g. 0.75)
+}
+
+// Adaptive is a Limiter whose capacity follows AIMD:
+//   - every task at or under Target adds 1/limit, so the limit grows by
+//     about one per "window" of limit fast tasks
+//   - a task over Target multiplies the limit by Backoff, at most once per
+//     window, so a burst of slow tasks counts as a single signal
+type Adaptive struct {
+	*Limiter
+	cfg AIMDConfig
+
+	mutex   sync.Mutex
+	credit  float64 // Fractional additive increase accumulated so far
+	holdoff int     // Observations to ignore after a decrease
+}
+
+// NewAdaptive creates an AIMD limiter
+// Parameters:
+//   - cfg: Bounds, latency target and backoff factor (zero Backoff means 0.75)
+//
+// Returns:
+//   - Pointer to initialized limiter
+func NewAdaptive(cfg AIMDConfig) *Adaptive {
+	cfg.Min = max(cfg.Min, 1)
+	cfg.Max = max(cfg.Max, cfg.Min)
+	cfg.Initial = min(max(cfg.Initial, cfg.Min), cfg.Max)
+	if cfg.Backoff <= 0 || cfg.Backoff >= 1 {
+		cfg.Backoff = 0.75
+	}
+	return &Adaptive{Limiter: New(cfg.Initial), cfg: cfg}
+}
+
+// Observe feeds one task latency into the controller
+func (a *Adaptive) Observe(latency time.Duration) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	limit := a.Limit()
+	if a.holdoff > 0 {
+		a.holdoff--
+		return
+	}
+
+	if latency > a.cfg.Target {
+		// ==================== MULTIPLICATIVE DECREASE ====================
+		next := max(int(float64(limit)*a.cfg.Backoff), a.cfg.Min)
+		a.SetLimit(next)
+		a.credit = 0
+		a.holdoff = limit // Ignore tasks that started under the old limit
+		return
+	}
+
+	// ==================== ADDITIVE INCREASE ====================
+	a.credit += 1 / float64(limit)
+	if a.credit >= 1 && limit < a.cfg.Max {
+		a.credit = 0
+		a.SetLimit(limit + 1)
+	}
+}
+
+// Do acquires a slot, runs fn, releases the slot and observes fn's latency
+// Returns:
+//   - ctx.Err() if no slot was obtained, else fn's error
+func (a *Adaptive) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := a.Acquire(ctx); err != nil {
+		return err
+	}
+	defer a.Release()
+
+	start := time.Now()
+	err := fn(ctx)
+	a.Observe(time.Since(start))
+	return err
+}
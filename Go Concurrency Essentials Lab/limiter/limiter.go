@@ -0,0 +1,185 @@
+// Go Concurrency Essentials - Resizable Concurrency Limiter
+// Description: Counting semaphore whose capacity can be changed while tasks
+//              are in flight, plus an AIMD mode that tunes the capacity from
+//              observed task latency
+//
+// Raising the limit admits waiting tasks immediately. Lowering it never
+// interrupts running tasks: new acquisitions wait until enough in-flight
+// tasks have released for the count to fall below the new limit
+
+package limiter
+
+import (
+	"context"
+	"sync"
+)
+
+// ==================== LIMITER ====================
+// Limiter bounds the number of concurrent holders; waiters are served FIFO
+type Limiter struct {
+	mutex    sync.Mutex
+	limit    int             // Current capacity
+	inFlight int             // Current holders
+	waiters  []chan struct{} // Blocked Acquire calls, oldest first (closed when granted)
+	drains   []drainWaiter   // Blocked Resize calls
+}
+
+// drainWaiter is a Resize call waiting for inFlight to reach its limit
+type drainWaiter struct {
+	limit int
+	ch    chan struct{}
+}
+
+// New creates a limiter with the given capacity (at least 1)
+func New(limit int) *Limiter {
+	return &Limiter{limit: max(limit, 1)}
+}
+
+// Acquire blocks until a slot is free or ctx is cancelled
+// Returns:
+//   - nil once the caller holds a slot, or ctx.Err()
+func (l *Limiter) Acquire(ctx context.Context) error {
+	l.mutex.Lock()
+	// Fast path: free slot and nobody queued ahead of us
+	if l.inFlight < l.limit && len(l.waiters) == 0 {
+		l.inFlight++
+		l.mutex.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	l.waiters = append(l.waiters, ch)
+	l.mutex.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		select {
+		case <-ch:
+			// Granted while we were cancelling: keep the slot
+			return nil
+		default:
+		}
+		for i, w := range l.waiters {
+			if w == ch {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				break
+			}
+		}
+		l.grant() // Our departure may unblock the next waiter
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes a slot only if one is free right now
+func (l *Limiter) TryAcquire() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.inFlight < l.limit && len(l.waiters) == 0 {
+		l.inFlight++
+		return true
+	}
+	return false
+}
+
+// Release returns a slot
+func (l *Limiter) Release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.inFlight == 0 {
+		panic("limiter: Release without Acquire")
+	}
+	l.inFlight--
+	l.grant()
+}
+
+// SetLimit changes the capacity without waiting
+// Raising it admits queued waiters at once; lowering it lets in-flight
+// tasks finish, and new tasks wait until the count drops below n
+func (l *Limiter) SetLimit(n int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.limit = max(n, 1)
+	l.grant()
+}
+
+// Resize changes the capacity and, when shrinking, waits until the number
+// of in-flight tasks has drained to the new limit
+// Returns:
+//   - nil once inFlight <= n, or ctx.Err() (the new limit stays in force)
+func (l *Limiter) Resize(ctx context.Context, n int) error {
+	l.mutex.Lock()
+	l.limit = max(n, 1)
+	l.grant()
+	if l.inFlight <= l.limit {
+		l.mutex.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	l.drains = append(l.drains, drainWaiter{limit: l.limit, ch: ch})
+	l.mutex.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		select {
+		case <-ch:
+			// Drained while we were cancelling: the resize completed
+			return nil
+		default:
+		}
+		for i, d := range l.drains {
+			if d.ch == ch {
+				l.drains = append(l.drains[:i], l.drains[i+1:]...)
+				break
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// grant admits waiters while there is capacity and wakes Resize calls
+// whose target has been reached. Caller holds l.mutex
+func (l *Limiter) grant() {
+	for l.inFlight < l.limit && len(l.waiters) > 0 {
+		l.inFlight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+
+	remaining := l.drains[:0]
+	for _, d := range l.drains {
+		if l.inFlight <= d.limit {
+			close(d.ch)
+		} else {
+			remaining = append(remaining, d)
+		}
+	}
+	l.drains = remaining
+}
+
+// Limit returns the current capacity
+func (l *Limiter) Limit() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.limit
+}
+
+// InFlight returns the number of current holders
+func (l *Limiter) InFlight() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.inFlight
+}
+
+// Waiting returns the number of blocked Acquire calls
+func (l *Limiter) Waiting() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.waiters)
+}
@@ -0,0 +1,260 @@
+// Go Concurrency Essentials - Lock-Order Checker (lockdep)
+// Description: Debug mutex that records, across all goroutines, which locks
+//              were held while another was acquired. Each "A held while
+//              taking B" adds an edge A -> B to a global order graph. An
+//              edge that closes a cycle means two code paths take the same
+//              locks in different orders, which can deadlock, so it is
+//              reported the first time it is seen even if nothing blocked
+//
+// A report names the cycle and, for every edge in it, the stacks where the
+// first lock was taken and where the second was requested. Taking a lock
+// the goroutine already holds is reported too. Checks run before blocking,
+// so a report is printed even when the acquisition then deadlocks
+//
+// This is a debugging aid: every Lock captures a stack and takes the
+// Checker's mutex
+
+package lockdep
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"essentials/stacks"
+)
+
+// ==================== REPORTS ====================
+// Edge is one observed ordering: From was held when To was acquired
+type Edge struct {
+	From, To   string
+	Goroutine  int64
+	HeldAt     []string // Stack where From was taken
+	AcquiredAt []string // Stack where To was requested
+}
+
+// Report describes one potential deadlock
+type Report struct {
+	Recursive bool     // A goroutine requested a lock it already holds
+	Cycle     []string // Lock names around the cycle, first repeated last
+	Edges     []Edge   // Edges of the cycle, the newly observed one first
+}
+
+// String formats the report with both stacks of every edge
+func (r Report) String() string {
+	var b strings.Builder
+	if r.Recursive {
+		fmt.Fprintf(&b, "lockdep: recursive locking of %s\n", r.Cycle[0])
+	} else {
+		fmt.Fprintf(&b, "lockdep: possible deadlock, lock order cycle %s\n", strings.Join(r.Cycle, " -> "))
+	}
+	for i, e := range r.Edges {
+		label := "earlier order"
+		if i == 0 {
+			label = "new order"
+		}
+		fmt.Fprintf(&b, "  %s: %s -> %s (goroutine %d)\n", label, e.From, e.To, e.Goroutine)
+		fmt.Fprintf(&b, "    %s taken at:\n", e.From)
+		for _, f := range e.HeldAt {
+			fmt.Fprintf(&b, "      %s\n", f)
+		}
+		fmt.Fprintf(&b, "    %s requested at:\n", e.To)
+		for _, f := range e.AcquiredAt {
+			fmt.Fprintf(&b, "      %s\n", f)
+		}
+	}
+	return b.String()
+}
+
+// resolve turns an internal edge into a reportable Edge
+func resolve(e *edge) Edge {
+	return Edge{
+		From:       e.from.name,
+		To:         e.to.name,
+		Goroutine:  e.goroutine,
+		HeldAt:     stacks.Frames(e.held),
+		AcquiredAt: stacks.Frames(e.wanted),
+	}
+}
+
+// =================================================
+
+// ==================== CHECKER ====================
+// edge is the internal form of Edge, stacks still as program counters
+type edge struct {
+	from, to     *Mutex
+	goroutine    int64
+	held, wanted []uintptr
+}
+
+// holding is one lock held by a goroutine
+type holding struct {
+	m   *Mutex
+	pcs []uintptr
+}
+
+// Checker owns an order graph and the locks that feed it
+type Checker struct {
+	mutex   sync.Mutex
+	next    map[*Mutex]map[*Mutex]*edge // Order graph: from -> to -> first sighting
+	held    map[int64][]holding         // Locks held by each goroutine
+	reports []Report
+	report  func(Report)
+}
+
+// NewChecker creates a checker that passes every report to onReport, or
+// prints it to standard error if onReport is nil
+func NewChecker(onReport func(Report)) *Checker {
+	if onReport == nil {
+		onReport = func(r Report) { io.WriteString(os.Stderr, r.String()) }
+	}
+	return &Checker{
+		next:   make(map[*Mutex]map[*Mutex]*edge),
+		held:   make(map[int64][]holding),
+		report: onReport,
+	}
+}
+
+// Reports returns every report so far, in order
+func (c *Checker) Reports() []Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return slices.Clone(c.reports)
+}
+
+// acquire records that goroutine g is requesting m while holding its
+// current locks, reporting any order this creates a cycle with
+func (c *Checker) acquire(m *Mutex, g int64, pcs []uintptr) {
+	c.mutex.Lock()
+	var found []Report
+	for _, h := range c.held[g] {
+		if h.m == m {
+			found = append(found, Report{
+				Recursive: true,
+				Cycle:     []string{m.name, m.name},
+				Edges:     []Edge{resolve(&edge{from: m, to: m, goroutine: g, held: h.pcs, wanted: pcs})},
+			})
+			continue
+		}
+		if _, seen := c.next[h.m][m]; seen {
+			continue
+		}
+		e := &edge{from: h.m, to: m, goroutine: g, held: h.pcs, wanted: pcs}
+		if path := c.path(m, h.m); path != nil {
+			// m already leads back to h.m: the new edge closes a cycle
+			r := Report{Cycle: []string{h.m.name, m.name}, Edges: []Edge{resolve(e)}}
+			for _, p := range path {
+				r.Cycle = append(r.Cycle, p.to.name)
+				r.Edges = append(r.Edges, resolve(p))
+			}
+			found = append(found, r)
+		}
+		if c.next[h.m] == nil {
+			c.next[h.m] = make(map[*Mutex]*edge)
+		}
+		c.next[h.m][m] = e
+	}
+	c.reports = append(c.reports, found...)
+	c.mutex.Unlock()
+
+	for _, r := range found {
+		c.report(r)
+	}
+}
+
+// path finds a chain of edges from -> ... -> to, or nil (caller holds c.mutex)
+func (c *Checker) path(from, to *Mutex) []*edge {
+	visited := map[*Mutex]bool{from: true}
+	var dfs func(at *Mutex) []*edge
+	dfs = func(at *Mutex) []*edge {
+		for next, e := range c.next[at] {
+			if next == to {
+				return []*edge{e}
+			}
+			if !visited[next] {
+				visited[next] = true
+				if rest := dfs(next); rest != nil {
+					return append([]*edge{e}, rest...)
+				}
+			}
+		}
+		return nil
+	}
+	return dfs(from)
+}
+
+// acquired records that goroutine g now holds m
+func (c *Checker) acquired(m *Mutex, g int64, pcs []uintptr) {
+	c.mutex.Lock()
+	c.held[g] = append(c.held[g], holding{m: m, pcs: pcs})
+	c.mutex.Unlock()
+}
+
+// released records that goroutine g no longer holds m (in any order)
+func (c *Checker) released(m *Mutex, g int64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	held := c.held[g]
+	for i := len(held) - 1; i >= 0; i-- {
+		if held[i].m == m {
+			held = slices.Delete(held, i, i+1)
+			break
+		}
+	}
+	if len(held) == 0 {
+		delete(c.held, g)
+	} else {
+		c.held[g] = held
+	}
+}
+
+// =================================================
+
+// ==================== MUTEX ====================
+// Mutex is a sync.Mutex whose acquisition order is checked
+type Mutex struct {
+	mutex sync.Mutex
+	name  string
+	c     *Checker
+	owner int64 // Goroutine holding mutex (guarded by mutex)
+}
+
+// NewMutex creates a checked mutex; name identifies it in reports
+func (c *Checker) NewMutex(name string) *Mutex {
+	return &Mutex{name: name, c: c}
+}
+
+// Name returns the mutex's name
+func (m *Mutex) Name() string { return m.name }
+
+// Lock checks the order against every lock the goroutine holds, then
+// acquires m
+func (m *Mutex) Lock() {
+	g, pcs := stacks.GoID(), stacks.Callers()
+	m.c.acquire(m, g, pcs)
+	m.mutex.Lock()
+	m.owner = g
+	m.c.acquired(m, g, pcs)
+}
+
+// TryLock acquires m if it is free. It never blocks, so it adds no order
+// edges, but locks taken while holding m are still ordered after it
+func (m *Mutex) TryLock() bool {
+	if !m.mutex.TryLock() {
+		return false
+	}
+	m.owner = stacks.GoID()
+	m.c.acquired(m, m.owner, stacks.Callers())
+	return true
+}
+
+// Unlock releases m (from any goroutine, as with sync.Mutex)
+func (m *Mutex) Unlock() {
+	m.c.released(m, m.owner)
+	m.mutex.Unlock()
+}
+
+// ===============================================
@@ -0,0 +1,210 @@
+// Go Concurrency Essentials - Lock Contention Profile
+// Description: Runs a small bank through instrument.Mutex and
+//              instrument.RWMutex and prints the ranked contention report:
+//              - deposits: many short holds of the accounts lock
+//              - audits: a few long holds of the same lock
+//              - rate lookups and reloads: readers and a writer on a
+//                config RWMutex
+//
+// Then it measures what the wrapper costs per uncontended Lock/Unlock,
+// with and without a Profiler. Exits 1 if the report's counts do not match
+// the work done or the audit is not the longest holder
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"essentials/instrument"
+)
+
+// bank is the shared state under test
+type bank struct {
+	mutex    *instrument.Mutex
+	accounts []int
+
+	config *instrument.RWMutex
+	rate   int // Interest rate in basis points
+}
+
+// deposit adds amount to one account (short critical section)
+func (b *bank) deposit(account, amount int) {
+	b.mutex.Lock()
+	b.accounts[account] += amount
+	b.mutex.Unlock()
+}
+
+// audit sums every account while holding the lock for a long time
+func (b *bank) audit(work time.Duration) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	sum := 0
+	for _, a := range b.accounts {
+		sum += a
+	}
+	time.Sleep(work) // Stands in for slow reporting under the lock
+	return sum
+}
+
+// currentRate reads the config (read lock)
+func (b *bank) currentRate() int {
+	b.config.RLock()
+	defer b.config.RUnlock()
+	return b.rate
+}
+
+// reload replaces the config (write lock)
+func (b *bank) reload(rate int) {
+	b.config.Lock()
+	b.rate = rate
+	b.config.Unlock()
+}
+
+// workload is how much of each operation to run
+type workload struct {
+	workers    int // Goroutines making deposits and reading the rate
+	deposits   int // Deposits per worker
+	pauseEvery int // Deposits between short pauses
+	audits     int
+	auditFor   time.Duration
+	reloads    int
+}
+
+// run drives b with w and returns the expected total balance
+func run(b *bank, w workload) int {
+	var wg sync.WaitGroup
+	for id := range w.workers {
+		wg.Go(func() {
+			for i := range w.deposits {
+				b.deposit((id+i)%len(b.accounts), 1+b.currentRate()%2)
+				if i%w.pauseEvery == 0 {
+					time.Sleep(100 * time.Microsecond) // Spread deposits across the audits
+				}
+			}
+		})
+	}
+	wg.Go(func() {
+		for range w.audits {
+			b.audit(w.auditFor)
+			time.Sleep(w.auditFor)
+		}
+	})
+	wg.Go(func() {
+		for i := range w.reloads {
+			b.reload(2 * i) // Even rates, so every deposit adds 1
+			time.Sleep(time.Millisecond)
+		}
+	})
+	wg.Wait()
+	return w.workers * w.deposits
+}
+
+// overhead measures ns per uncontended Lock/Unlock pair
+func overhead(l sync.Locker, n int) float64 {
+	start := time.Now()
+	for range n {
+		l.Lock()
+		l.Unlock()
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(n)
+}
+
+// check compares the report with the work that was done
+// Returns:
+//   - Descriptions of every mismatch
+func check(r instrument.Report, w workload, balance, want int) []string {
+	var problems []string
+	if balance != want {
+		problems = append(problems, fmt.Sprintf("balance %d, want %d", balance, want))
+	}
+
+	count := func(lock, mode, fn string) int64 {
+		var n int64
+		for _, s := range r.Sites {
+			if s.Lock == lock && s.Mode == mode && strings.Contains(s.Site, fn) {
+				n += s.Acquisitions
+			}
+		}
+		return n
+	}
+	expect := []struct {
+		lock, mode, fn string
+		want           int
+	}{
+		{"accounts", "write", "deposit", w.workers * w.deposits},
+		{"accounts", "write", "audit", w.audits + 1}, // Plus the final audit
+		{"config", "read", "currentRate", w.workers * w.deposits},
+		{"config", "write", "reload", w.reloads},
+	}
+	for _, e := range expect {
+		if got := count(e.lock, e.mode, e.fn); got != int64(e.want) {
+			problems = append(problems, fmt.Sprintf("%s %s acquisitions from %s: %d, want %d", e.lock, e.mode, e.fn, got, e.want))
+		}
+	}
+	if len(r.Longest) == 0 || !strings.Contains(r.Longest[0].Site, "audit") {
+		problems = append(problems, "longest hold is not an audit")
+	}
+	return problems
+}
+
+// main runs the workload, prints the report and measures overhead
+func main() {
+	format := flag.String("format", "table", "report format: table or json")
+	workers := flag.Int("workers", 8, "goroutines making deposits")
+	deposits := flag.Int("deposits", 5000, "deposits per worker")
+	flag.Parse()
+	if *format != "table" && *format != "json" {
+		log.Fatalf("unknown -format %q (want table or json)", *format)
+	}
+
+	w := workload{
+		workers:    *workers,
+		deposits:   *deposits,
+		pauseEvery: 100,
+		audits:     5,
+		auditFor:   2 * time.Millisecond,
+		reloads:    10,
+	}
+
+	// ==================== PROFILE ====================
+	prof := instrument.NewProfiler()
+	b := &bank{
+		mutex:    instrument.NewMutex("accounts", prof),
+		accounts: make([]int, 16),
+		config:   instrument.NewRWMutex("config", prof),
+	}
+	want := run(b, w)
+	balance := b.audit(0)
+
+	report := prof.Report()
+	write := report.WriteTable
+	if *format == "json" {
+		write = report.WriteJSON
+	}
+	if err := write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	// =================================================
+
+	// ==================== OVERHEAD ====================
+	const n = 1_000_000
+	fmt.Fprintf(os.Stderr, "\nuncontended Lock/Unlock (ns/op)\n")
+	fmt.Fprintf(os.Stderr, "  sync.Mutex                 %6.1f\n", overhead(&sync.Mutex{}, n))
+	fmt.Fprintf(os.Stderr, "  instrument.Mutex, no prof  %6.1f\n", overhead(instrument.NewMutex("bench", nil), n))
+	fmt.Fprintf(os.Stderr, "  instrument.Mutex, profiled %6.1f\n", overhead(instrument.NewMutex("bench", instrument.NewProfiler()), n))
+	// ==================================================
+
+	if problems := check(report, w, balance, want); len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Fprintln(os.Stderr, "FAIL:", p)
+		}
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "\nreport matches the work done: ok")
+}
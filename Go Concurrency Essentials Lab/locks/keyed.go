@@ -0,0 +1,90 @@
+// Go Concurrency Essentials - Mutex Variants: Keyed
+// Description: One mutex per key (e.g. per account ID), created on first
+//              use and removed again when nobody holds or waits for it, so
+//              the map only holds keys that are in use
+//
+// Each entry counts its holders and waiters; the last Unlock deletes it
+
+package locks
+
+import "sync"
+
+// keyedEntry is the mutex for one key plus how many goroutines want it
+type keyedEntry struct {
+	mutex sync.Mutex
+	refs  int // Holders and waiters (guarded by Keyed.mutex)
+}
+
+// Keyed locks per key; different keys never block each other
+type Keyed[K comparable] struct {
+	mutex   sync.Mutex
+	entries map[K]*keyedEntry
+}
+
+// NewKeyed creates an empty keyed mutex
+func NewKeyed[K comparable]() *Keyed[K] {
+	return &Keyed[K]{entries: make(map[K]*keyedEntry)}
+}
+
+// Lock acquires the mutex for key
+func (k *Keyed[K]) Lock(key K) {
+	k.mutex.Lock()
+	e, ok := k.entries[key]
+	if !ok {
+		e = &keyedEntry{}
+		k.entries[key] = e
+	}
+	e.refs++
+	k.mutex.Unlock()
+
+	e.mutex.Lock()
+}
+
+// TryLock acquires the mutex for key only if it is free
+func (k *Keyed[K]) TryLock(key K) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if _, held := k.entries[key]; held {
+		return false
+	}
+	e := &keyedEntry{refs: 1}
+	e.mutex.Lock()
+	k.entries[key] = e
+	return true
+}
+
+// Unlock releases the mutex for key, dropping it if nobody else wants it;
+// it panics if key is not locked
+func (k *Keyed[K]) Unlock(key K) {
+	k.mutex.Lock()
+	e, ok := k.entries[key]
+	if !ok {
+		k.mutex.Unlock()
+		panic("locks: unlock of unlocked key")
+	}
+	e.refs--
+	if e.refs == 0 {
+		delete(k.entries, key)
+	}
+	k.mutex.Unlock()
+
+	e.mutex.Unlock()
+}
+
+// Len returns how many keys are held or waited for
+func (k *Keyed[K]) Len() int {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return len(k.entries)
+}
+
+// Locker returns a sync.Locker for one key
+func (k *Keyed[K]) Locker(key K) sync.Locker { return keyLocker[K]{k, key} }
+
+type keyLocker[K comparable] struct {
+	k   *Keyed[K]
+	key K
+}
+
+func (l keyLocker[K]) Lock()   { l.k.Lock(l.key) }
+func (l keyLocker[K]) Unlock() { l.k.Unlock(l.key) }
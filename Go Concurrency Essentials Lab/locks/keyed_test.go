@@ -0,0 +1,75 @@
+// Go Concurrency Essentials - Keyed Mutex Tests
+// Description: The adds pattern on one shared key and on ten account keys,
+//              then key independence and cleanup of unused keys
+
+package locks
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestKeyedOneKey(t *testing.T) {
+	k := NewKeyed[string]()
+
+	// All goroutines share one key, as in mutex.go
+	total := 0
+	adds(func(int) sync.Locker { return k.Locker("total") }, &total)
+	if total != want {
+		t.Errorf("total %d, want %d", total, want)
+	}
+	if n := k.Len(); n != 0 {
+		t.Errorf("%d keys left after use, want 0", n)
+	}
+}
+
+func TestKeyedManyKeys(t *testing.T) {
+	k := NewKeyed[string]()
+
+	// Ten accounts, every goroutine depositing into all of them
+	accounts := make([]int, 10)
+	var wg sync.WaitGroup
+	for g := range goroutines {
+		wg.Go(func() {
+			for i := range increments {
+				id := (g + i) % len(accounts)
+				key := fmt.Sprintf("account-%d", id)
+				k.Lock(key)
+				accounts[id]++
+				k.Unlock(key)
+			}
+		})
+	}
+	wg.Wait()
+	sum := 0
+	for _, a := range accounts {
+		sum += a
+	}
+	if sum != want {
+		t.Errorf("total %d, want %d", sum, want)
+	}
+	if n := k.Len(); n != 0 {
+		t.Errorf("%d keys left after use, want 0", n)
+	}
+}
+
+func TestKeyedIndependentKeys(t *testing.T) {
+	k := NewKeyed[string]()
+	k.Lock("a")
+	if !k.TryLock("b") {
+		t.Fatal("holding key a blocked key b")
+	}
+	if k.TryLock("a") {
+		t.Fatal("key a locked twice")
+	}
+	if n := k.Len(); n != 2 {
+		t.Errorf("%d keys while two are held, want 2", n)
+	}
+	k.Unlock("b")
+	k.Unlock("a")
+	if n := k.Len(); n != 0 {
+		t.Errorf("%d keys after unlock, want 0", n)
+	}
+	mustPanic(t, "Unlock of unlocked key", func() { k.Unlock("a") })
+}
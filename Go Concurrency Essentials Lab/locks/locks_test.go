@@ -0,0 +1,45 @@
+// Go Concurrency Essentials - Mutex Variant Test Helpers
+// Description: The adds pattern from mutex.go (10 goroutines x 1000
+//              increments of a plain int), shared by the tests of each
+//              variant. Run with -race to have the race detector confirm
+//              the counters are protected
+
+package locks
+
+import (
+	"sync"
+	"testing"
+)
+
+const (
+	goroutines = 10
+	increments = 1000
+	want       = goroutines * increments
+)
+
+// adds is the loop from mutex.go, once per goroutine
+func adds(lock func(id int) sync.Locker, counter *int) {
+	var wg sync.WaitGroup
+	for id := range goroutines {
+		wg.Go(func() {
+			l := lock(id)
+			for range increments {
+				l.Lock()
+				*counter++
+				l.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// mustPanic fails t unless f panics
+func mustPanic(t *testing.T, what string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", what)
+		}
+	}()
+	f()
+}
@@ -0,0 +1,97 @@
+// Go Concurrency Essentials - Mutex Variants: Reentrant
+// Description: A mutex the same owner may lock again while holding it.
+//              Go has no goroutine identity, so callers identify
+//              themselves with an Owner token that they pass along to
+//              any code that may re-lock
+//
+// Each Lock by the owner must be matched by an Unlock; the mutex is free
+// again when the count returns to zero
+
+package locks
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Owner identifies who holds a Reentrant mutex
+type Owner uint64
+
+// lastOwner hands out unique Owner tokens
+var lastOwner atomic.Uint64
+
+// NewOwner returns a token no other caller has
+func NewOwner() Owner { return Owner(lastOwner.Add(1)) }
+
+// Reentrant is a mutex that its owner may lock recursively
+type Reentrant struct {
+	mutex sync.Mutex
+	free  *sync.Cond // Signalled when depth drops to zero
+	owner Owner
+	depth int
+}
+
+// NewReentrant creates an unlocked reentrant mutex
+func NewReentrant() *Reentrant {
+	r := &Reentrant{}
+	r.free = sync.NewCond(&r.mutex)
+	return r
+}
+
+// Lock acquires the mutex for o, or deepens o's hold if it already has it
+func (r *Reentrant) Lock(o Owner) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for r.depth > 0 && r.owner != o {
+		r.free.Wait()
+	}
+	r.owner = o
+	r.depth++
+}
+
+// TryLock is Lock without waiting
+// Returns:
+//   - true if o now holds the mutex
+func (r *Reentrant) TryLock(o Owner) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.depth > 0 && r.owner != o {
+		return false
+	}
+	r.owner = o
+	r.depth++
+	return true
+}
+
+// Unlock undoes one Lock by o; it panics if o does not hold the mutex
+func (r *Reentrant) Unlock(o Owner) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.depth == 0 || r.owner != o {
+		panic(fmt.Sprintf("locks: Reentrant unlocked by owner %d, held by %d (depth %d)", o, r.owner, r.depth))
+	}
+	r.depth--
+	if r.depth == 0 {
+		r.owner = 0
+		r.free.Signal()
+	}
+}
+
+// Depth returns how many times the current owner holds the mutex
+func (r *Reentrant) Depth() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.depth
+}
+
+// Locker returns a sync.Locker that locks r as o
+func (r *Reentrant) Locker(o Owner) sync.Locker { return ownedLocker{r, o} }
+
+type ownedLocker struct {
+	r *Reentrant
+	o Owner
+}
+
+func (l ownedLocker) Lock()   { l.r.Lock(l.o) }
+func (l ownedLocker) Unlock() { l.r.Unlock(l.o) }
@@ -0,0 +1,73 @@
+// Go Concurrency Essentials - Reentrant Mutex Tests
+// Description: Nested locking by one owner under the adds pattern, and
+//              panics when anyone else unlocks
+
+package locks
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReentrantNested(t *testing.T) {
+	r := NewReentrant()
+
+	// Every increment re-locks: the helper locks again while the caller holds it
+	total := 0
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			me := NewOwner()
+			increment := func() {
+				r.Lock(me) // Already held by me: depth 2
+				total++
+				r.Unlock(me)
+			}
+			for range increments {
+				r.Lock(me)
+				increment()
+				r.Unlock(me)
+			}
+		})
+	}
+	wg.Wait()
+	if total != want {
+		t.Errorf("total %d, want %d", total, want)
+	}
+	if d := r.Depth(); d != 0 {
+		t.Errorf("depth %d after all unlocks, want 0", d)
+	}
+}
+
+func TestReentrantLocker(t *testing.T) {
+	r := NewReentrant()
+	total := 0
+	owners := make([]Owner, goroutines)
+	for i := range owners {
+		owners[i] = NewOwner()
+	}
+	adds(func(id int) sync.Locker { return r.Locker(owners[id]) }, &total)
+	if total != want {
+		t.Errorf("total %d, want %d", total, want)
+	}
+}
+
+func TestReentrantOwnership(t *testing.T) {
+	r := NewReentrant()
+	a, b := NewOwner(), NewOwner()
+	r.Lock(a)
+	r.Lock(a)
+	if d := r.Depth(); d != 2 {
+		t.Fatalf("depth %d after locking twice, want 2", d)
+	}
+	if r.TryLock(b) {
+		t.Fatal("other owner locked a held Reentrant")
+	}
+	mustPanic(t, "Unlock by other owner", func() { r.Unlock(b) })
+	r.Unlock(a)
+	r.Unlock(a)
+	if !r.TryLock(b) {
+		t.Fatal("other owner cannot lock once released")
+	}
+	r.Unlock(b)
+}
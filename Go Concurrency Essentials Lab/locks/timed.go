@@ -0,0 +1,71 @@
+// Go Concurrency Essentials - Mutex Variants: Timed
+// Description: A mutex whose Lock can give up, after a timeout
+//              (TryLockFor) or when a context ends (LockContext)
+//
+// It is the one-slot channel semaphore from semaphore.go: locking sends
+// into the channel, unlocking receives, so waiting can sit in a select
+
+package locks
+
+import (
+	"context"
+	"time"
+)
+
+// Timed is a mutex with cancellable acquisition
+type Timed struct {
+	slot chan struct{}
+}
+
+// NewTimed creates an unlocked Timed mutex
+func NewTimed() *Timed {
+	return &Timed{slot: make(chan struct{}, 1)}
+}
+
+// Lock waits as long as it takes
+func (t *Timed) Lock() { t.slot <- struct{}{} }
+
+// TryLock acquires the mutex only if it is free
+func (t *Timed) TryLock() bool {
+	select {
+	case t.slot <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// TryLockFor waits at most d
+// Returns:
+//   - true if the mutex was acquired
+func (t *Timed) TryLockFor(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case t.slot <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// LockContext waits until the mutex is acquired or ctx is done
+// Returns:
+//   - nil once locked, or ctx's error (the mutex is then not held)
+func (t *Timed) LockContext(ctx context.Context) error {
+	select {
+	case t.slot <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock releases the mutex; it panics if the mutex is not locked
+func (t *Timed) Unlock() {
+	select {
+	case <-t.slot:
+	default:
+		panic("locks: unlock of unlocked Timed")
+	}
+}
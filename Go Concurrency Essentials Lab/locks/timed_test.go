@@ -0,0 +1,77 @@
+// Go Concurrency Essentials - Timed Mutex Tests
+// Description: The adds pattern through TryLockFor and LockContext, then
+//              timeouts, cancellation and unlock of an unlocked mutex
+
+package locks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timedFor locks with TryLockFor, retrying until it succeeds
+type timedFor struct{ t *Timed }
+
+func (l timedFor) Lock() {
+	for !l.t.TryLockFor(time.Millisecond) {
+	}
+}
+func (l timedFor) Unlock() { l.t.Unlock() }
+
+// timedCtx locks with LockContext
+type timedCtx struct{ t *Timed }
+
+func (l timedCtx) Lock() {
+	if err := l.t.LockContext(context.Background()); err != nil {
+		panic(err)
+	}
+}
+func (l timedCtx) Unlock() { l.t.Unlock() }
+
+func TestTimedAdds(t *testing.T) {
+	m := NewTimed()
+
+	// Half the goroutines use TryLockFor, half LockContext
+	total := 0
+	adds(func(id int) sync.Locker {
+		if id%2 == 0 {
+			return timedFor{m}
+		}
+		return timedCtx{m}
+	}, &total)
+	if total != want {
+		t.Errorf("total %d, want %d", total, want)
+	}
+}
+
+func TestTimedGivesUp(t *testing.T) {
+	m := NewTimed()
+	m.Lock()
+
+	start := time.Now()
+	if m.TryLockFor(20 * time.Millisecond) {
+		t.Fatal("TryLockFor succeeded on a held mutex")
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("TryLockFor(20ms) gave up after %v", waited)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := m.LockContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("LockContext after cancel = %v, want context.Canceled", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.Unlock()
+	}()
+	if !m.TryLockFor(time.Second) {
+		t.Fatal("TryLockFor failed after the holder unlocked")
+	}
+	m.Unlock()
+	mustPanic(t, "Unlock of unlocked Timed", m.Unlock)
+}
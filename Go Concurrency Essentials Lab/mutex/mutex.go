@@ -1,12 +1,22 @@
 // Go Concurrency Essentials - Mutex Example
 // Description: Demonstrates traditional mutex-based synchronization
 //              for protecting shared state
+//
+// adds takes any sync.Locker, so -lock swaps sync.Mutex for one of the
+// spinlocks in the spinlock package, and -profile swaps it for an
+// instrument.Mutex and prints its contention report
 
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
+	"os"
 	"sync"
+
+	"essentials/instrument"
+	"essentials/spinlock"
 )
 
 // Global variables (passed by reference in practice)
@@ -16,11 +26,11 @@ var total int64 // Shared counter (not thread-safe without mutex)
 // adds increments a counter in a loop with mutex protection
 // Parameters:
 //   - n: Number of times to increment
-//   - theLock: Mutex to protect shared counter
+//   - theLock: Lock to protect shared counter (sync.Mutex or a spinlock)
 //
 // Returns:
 //   - bool: Always true (success indicator)
-func adds(n int, theLock *sync.Mutex) bool {
+func adds(n int, theLock sync.Locker) bool {
 	for range n {
 		// ==================== CRITICAL SECTION ====================
 		theLock.Lock()   // Acquire exclusive access
@@ -35,8 +45,36 @@ func adds(n int, theLock *sync.Mutex) bool {
 // main demonstrates mutex synchronization with multiple goroutines
 // Result: 10 goroutines × 1000 increments each = 10,000 (always correct)
 func main() {
+	lockName := flag.String("lock", "mutex", "lock to use: mutex, tas, ttas, ticket, mcs or clh")
+	profile := flag.String("profile", "", "profile the mutex and print a report: table or json")
+	flag.Parse()
+
 	// Mutex passed by reference (better than global, though still used here for demo)
-	var theLock sync.Mutex
+	var theLock sync.Locker = &sync.Mutex{}
+	var prof *instrument.Profiler
+	switch *profile {
+	case "", "table", "json":
+	default:
+		log.Fatalf("unknown -profile %q (want table or json)", *profile)
+	}
+	if *profile != "" {
+		if *lockName != "mutex" {
+			log.Fatal("-profile only works with -lock mutex")
+		}
+		prof = instrument.NewProfiler()
+		theLock = instrument.NewMutex("theLock", prof)
+	}
+	if *lockName != "mutex" {
+		theLock = nil
+		for _, l := range spinlock.All {
+			if l.Name == *lockName {
+				theLock = l.New()
+			}
+		}
+		if theLock == nil {
+			log.Fatalf("unknown -lock %q", *lockName)
+		}
+	}
 
 	total = 0
 	wg.Add(10) // Initialize WaitGroup for 10 goroutines
@@ -44,11 +82,23 @@ func main() {
 	// Launch 10 goroutines, each incrementing 1000 times
 	for i := range 10 {
 		fmt.Println(i)
-		go adds(1000, &theLock)
+		go adds(1000, theLock)
 	}
 
 	wg.Wait() // Wait for all goroutines to complete
 
 	// Print final value (should always be 10,000)
 	fmt.Println(total)
+
+	if prof != nil {
+		fmt.Println()
+		report := prof.Report()
+		write := report.WriteTable
+		if *profile == "json" {
+			write = report.WriteJSON
+		}
+		if err := write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
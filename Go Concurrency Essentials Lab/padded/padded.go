@@ -0,0 +1,40 @@
+// Go Concurrency Essentials - Padded Atomics
+// Description: Atomic types padded to a full cache line, for hot values
+//              written by different goroutines
+//
+// CPUs keep caches coherent one line (64 bytes on most hardware) at a time.
+// Two unrelated counters in the same line "falsely share" it: a write to
+// either invalidates the line in every other core's cache, so independent
+// goroutines slow each other down. Each type here is padded so neighbouring
+// values in a slice or struct are at least a line apart and never share one
+
+package padded
+
+import "sync/atomic"
+
+// CacheLineSize is the coherence unit assumed for padding
+const CacheLineSize = 64
+
+// Int64 is an atomic.Int64 that occupies a whole cache line
+type Int64 struct {
+	atomic.Int64
+	_ [CacheLineSize - 8]byte
+}
+
+// Uint64 is an atomic.Uint64 that occupies a whole cache line
+type Uint64 struct {
+	atomic.Uint64
+	_ [CacheLineSize - 8]byte
+}
+
+// Int32 is an atomic.Int32 that occupies a whole cache line
+type Int32 struct {
+	atomic.Int32
+	_ [CacheLineSize - 4]byte
+}
+
+// Bool is an atomic.Bool that occupies a whole cache line
+type Bool struct {
+	atomic.Bool
+	_ [CacheLineSize - 4]byte
+}
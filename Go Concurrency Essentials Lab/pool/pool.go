@@ -0,0 +1,253 @@
+// Go Concurrency Essentials - Bounded Worker Pool
+// Description: Reusable "acquire slot, spawn goroutine, release on exit" pool
+//              shared by the semaphore and sem-ex demos
+//
+// Features:
+// - Configurable maximum number of concurrently running tasks
+// - First error (or panic) cancels every other task's context
+// - Panics inside tasks are captured as errors instead of crashing
+// - Per-task results reported in submission order
+// - Each running task knows which worker slot it occupies (WorkerID)
+// - Any Limiter can bound concurrency, e.g. a resizable or adaptive one
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"essentials/weighted"
+)
+
+// Task is the unit of work run by the pool
+// The context is cancelled when the submitter's context is cancelled or
+// when another task in the pool fails
+type Task func(ctx context.Context) error
+
+// Result describes how one submitted task finished
+type Result struct {
+	Index    int           // Submission order, starting at 0
+	Worker   int           // Worker slot that ran the task (0..maxWorkers-1)
+	Err      error         // Error returned (or panic captured) by the task
+	Duration time.Duration // Time spent running the task
+}
+
+// workerKey is the context key holding a task's worker slot
+type workerKey struct{}
+
+// WorkerID returns the worker slot of the task owning ctx
+// Returns:
+//   - The slot (0..maxWorkers-1) and true, or 0 and false outside a pool task
+//     (with a resizable limiter, below the highest limit reached)
+func WorkerID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(workerKey{}).(int)
+	return id, ok
+}
+
+// PanicError wraps a value recovered from a panicking task
+type PanicError struct {
+	Value any    // Value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Unwrap exposes the panic value when it is itself an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ==================== LIMITERS ====================
+// Limiter hands out the slots that bound how many tasks run at once
+type Limiter interface {
+	Acquire(ctx context.Context) error
+	Release()
+}
+
+// Observer is implemented by limiters that adapt to task latency
+// The pool reports every task's duration before releasing its slot
+type Observer interface {
+	Observe(latency time.Duration)
+}
+
+// fixed adapts a FIFO weighted.Semaphore to Limiter, one token per task
+type fixed struct {
+	sem *weighted.Semaphore
+}
+
+func (f fixed) Acquire(ctx context.Context) error { return f.sem.Acquire(ctx, 1) }
+func (f fixed) Release()                          { f.sem.Release(1) }
+
+// ==================================================
+
+// ==================== POOL ====================
+// Pool runs submitted tasks with at most maxWorkers in flight
+type Pool struct {
+	sem    Limiter                 // One slot per running task
+	ctx    context.Context         // Cancelled on first failure or by Wait
+	cancel context.CancelCauseFunc // Cancels ctx with the first error
+	wg     sync.WaitGroup          // Tracks running tasks
+
+	mutex   sync.Mutex // Protects the fields below
+	next    int        // Index of the next submitted task
+	free    []int      // Worker IDs available for reuse
+	workers int        // Worker IDs handed out so far
+	err     error      // First task error
+	results []Result   // Finished tasks, in completion order
+}
+
+// New creates a pool that runs at most maxWorkers tasks at once
+// Parameters:
+//   - ctx: Parent context; cancelling it cancels every task
+//   - maxWorkers: Concurrency limit (values below 1 are treated as 1)
+//
+// Returns:
+//   - Pointer to initialized pool
+func New(ctx context.Context, maxWorkers int) *Pool {
+	sem := weighted.New(int64(max(maxWorkers, 1)))
+	return NewWithLimiter(ctx, fixed{sem: sem})
+}
+
+// NewWithLimiter creates a pool whose concurrency is bounded by limiter
+// If limiter implements Observer it is told every task's duration
+// Parameters:
+//   - ctx: Parent context; cancelling it cancels every task
+//   - limiter: Source of slots (e.g. a resizable limiter.Limiter)
+//
+// Returns:
+//   - Pointer to initialized pool
+func NewWithLimiter(ctx context.Context, limiter Limiter) *Pool {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Pool{
+		sem:    limiter,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Submit waits for a free slot, then runs task in a new goroutine
+// Blocks while maxWorkers tasks are in flight
+// Parameters:
+//   - ctx: Bounds the wait for a slot and is the parent of the task's context
+//   - task: Work to run
+//
+// Returns:
+//   - nil once the task has started, or an error if ctx was cancelled or
+//     the pool was cancelled by an earlier failure (the task does not run)
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	if p.ctx.Err() != nil {
+		return context.Cause(p.ctx)
+	}
+
+	// Task context: cancelled by the submitter or by the pool
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(p.ctx, func() { cancel(context.Cause(p.ctx)) })
+
+	// ==================== ACQUIRE SLOT ====================
+	if err := p.sem.Acquire(taskCtx); err != nil {
+		stop()
+		cancel(nil)
+		return context.Cause(taskCtx)
+	}
+	// ======================================================
+
+	p.mutex.Lock()
+	index := p.next
+	p.next++
+	worker := p.takeWorkerID()
+	p.mutex.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.sem.Release() // Release slot when task completes
+		defer cancel(nil)
+		defer stop()
+
+		start := time.Now()
+		err := run(context.WithValue(taskCtx, workerKey{}, worker), task)
+		elapsed := time.Since(start)
+
+		if o, ok := p.sem.(Observer); ok {
+			o.Observe(elapsed)
+		}
+		p.finish(Result{Index: index, Worker: worker, Err: err, Duration: elapsed})
+	}()
+	return nil
+}
+
+// run calls task, converting a panic into a *PanicError
+func run(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return task(ctx)
+}
+
+// takeWorkerID reuses the lowest free worker ID, or hands out a new one
+// IDs stay below the largest number of tasks ever in flight at once.
+// Caller holds p.mutex
+func (p *Pool) takeWorkerID() int {
+	if len(p.free) == 0 {
+		p.workers++
+		return p.workers - 1
+	}
+	lowest := 0
+	for i, id := range p.free {
+		if id < p.free[lowest] {
+			lowest = i
+		}
+	}
+	id := p.free[lowest]
+	p.free = append(p.free[:lowest], p.free[lowest+1:]...)
+	return id
+}
+
+// finish records a result and frees its worker ID; the first error
+// cancels the remaining tasks
+func (p *Pool) finish(r Result) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.free = append(p.free, r.Worker)
+	p.results = append(p.results, r)
+	if r.Err != nil && p.err == nil {
+		p.err = r.Err
+		p.cancel(r.Err)
+	}
+}
+
+// Wait blocks until every started task has finished
+// The pool cannot accept new tasks afterwards
+// Returns:
+//   - The first error returned (or panic raised) by any task, else nil
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	p.cancel(nil) // Release the context's resources
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.err
+}
+
+// Results returns one Result per started task, in submission order
+// Call after Wait to see every task
+func (p *Pool) Results() []Result {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	out := append([]Result(nil), p.results...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Index < out[j].Index })
+	return out
+}
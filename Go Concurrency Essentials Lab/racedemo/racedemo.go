@@ -0,0 +1,129 @@
+// Go Concurrency Essentials - Happens-Before Race Detector Demo
+// Description: Runs the adds counter from mutex.go through racedetect:
+//              - without the mutex: every goroutine's load and store of
+//                total races with the others, and the detector says where
+//              - with a racedetect.Mutex: Unlock -> Lock orders every access
+//              - a channel hand-off: the send orders the producer's store
+//                before the consumer's load
+//
+// The detector reasons about ordering, not outcomes: the racy run is
+// reported whatever total it happens to reach, even a correct 10,000.
+// Exits 1 unless only the racy run is reported
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"essentials/racedetect"
+)
+
+// addsRacy is adds from mutex.go with the lock removed
+// Parameters:
+//   - n: Number of times to increment
+//   - total: Shared counter
+func addsRacy(n int, total *racedetect.Var[int]) {
+	for range n {
+		total.Store(total.Load() + 1) // total++ with no lock: load, then store
+	}
+}
+
+// adds is adds from mutex.go
+// Parameters:
+//   - n: Number of times to increment
+//   - total: Shared counter
+//   - theLock: Lock protecting total
+func adds(n int, total *racedetect.Var[int], theLock sync.Locker) {
+	for range n {
+		// ==================== CRITICAL SECTION ====================
+		theLock.Lock()
+		total.Store(total.Load() + 1)
+		theLock.Unlock()
+		// ==========================================================
+	}
+}
+
+// counter runs 10 goroutines x 1000 increments with or without the lock
+// Returns:
+//   - Final total and the detector's reports
+func counter(locked bool) (int, []racedetect.Report) {
+	d := racedetect.NewDetector(func(racedetect.Report) {}) // Printed by main
+	total := racedetect.NewVar(d, "total", 0)
+	theLock := d.NewMutex()
+	wg := d.NewWaitGroup()
+	for range 10 {
+		wg.Go(func() {
+			if locked {
+				adds(1000, total, theLock)
+			} else {
+				addsRacy(1000, total)
+			}
+		})
+	}
+	wg.Wait()
+	return total.Load(), d.Reports() // Wait orders this load after every store
+}
+
+// handoff passes a result from a producer to a consumer over a channel
+// Returns:
+//   - The detector's reports
+func handoff() []racedetect.Report {
+	d := racedetect.NewDetector(func(racedetect.Report) {})
+	result := racedetect.NewVar(d, "result", 0)
+	ready := racedetect.NewChan[struct{}](d, 1)
+	wg := d.NewWaitGroup()
+	wg.Go(func() {
+		result.Store(42) // Producer
+		ready.Send(struct{}{})
+	})
+	wg.Go(func() {
+		ready.Recv()
+		_ = result.Load() // Consumer, ordered by the send
+	})
+	wg.Wait()
+	return d.Reports()
+}
+
+// main runs the three scenarios and checks which were reported
+func main() {
+	failed := false
+
+	// ==================== RACY ====================
+	total, reports := counter(false)
+	fmt.Printf("adds without a lock: total %d, %d race report(s)\n", total, len(reports))
+	if len(reports) == 0 {
+		failed = true
+	} else {
+		fmt.Println()
+		fmt.Print(reports[0])
+		fmt.Println()
+	}
+	// ==============================================
+
+	// ==================== LOCKED ====================
+	total, reports = counter(true)
+	fmt.Printf("adds with racedetect.Mutex: total %d, %d race report(s)\n", total, len(reports))
+	if len(reports) != 0 || total != 10000 {
+		failed = true
+	}
+	// ================================================
+
+	// ==================== CHANNEL ====================
+	reports = handoff()
+	fmt.Printf("channel hand-off: %d race report(s)\n", len(reports))
+	if len(reports) != 0 {
+		failed = true
+	}
+	// =================================================
+
+	for _, r := range reports {
+		fmt.Print(r)
+	}
+	if failed {
+		fmt.Println("\nFAIL")
+		os.Exit(1)
+	}
+	fmt.Println("\nok: only the unlocked counter is reported")
+}
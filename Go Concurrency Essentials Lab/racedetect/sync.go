@@ -0,0 +1,139 @@
+// Go Concurrency Essentials - Happens-Before Race Detector: Synchronisation
+// Description: Wrappers around sync.Mutex, channels and sync.WaitGroup
+//              that carry vector clocks along the happens-before edges the
+//              Go memory model gives them:
+//              - Mutex: Unlock happens before the next Lock
+//              - Chan: a send happens before the receive that gets it, and
+//                Close before a receive that sees the channel closed
+//              - WaitGroup: every Done happens before Wait returns
+//
+// The receive-before-send edges of unbuffered and full channels are not
+// modelled, so code that relies only on those is reported as racy
+
+package racedetect
+
+import "sync"
+
+// ==================== MUTEX ====================
+// Mutex is a sync.Mutex whose lock hand-offs order accesses
+type Mutex struct {
+	d     *Detector
+	mutex sync.Mutex
+	vc    vclock // Clock of the last Unlock (guarded by mutex)
+}
+
+// NewMutex creates an unlocked Mutex
+func (d *Detector) NewMutex() *Mutex { return &Mutex{d: d} }
+
+// Lock acquires the mutex and everything the last holder did
+func (m *Mutex) Lock() {
+	m.mutex.Lock()
+	if m.vc != nil {
+		m.d.acquire(m.vc)
+	}
+}
+
+// Unlock publishes the holder's history to the next Lock
+func (m *Mutex) Unlock() {
+	m.vc = m.d.release()
+	m.mutex.Unlock()
+}
+
+// ===============================================
+
+// ==================== CHANNEL ====================
+// message is a value sent with the sender's clock
+type message[T any] struct {
+	value T
+	vc    vclock
+}
+
+// Chan is a channel whose sends order accesses before the matching receive
+type Chan[T any] struct {
+	d       *Detector
+	ch      chan message[T]
+	closeMu sync.Mutex
+	closeVC vclock // Clock at Close
+}
+
+// NewChan creates a channel with the given buffer size
+func NewChan[T any](d *Detector, size int) *Chan[T] {
+	return &Chan[T]{d: d, ch: make(chan message[T], size)}
+}
+
+// Send sends v along with everything the sender did before
+func (c *Chan[T]) Send(v T) {
+	c.ch <- message[T]{value: v, vc: c.d.release()}
+}
+
+// Recv receives a value and the sender's history
+// Returns:
+//   - The value, and false once the channel is closed and drained
+func (c *Chan[T]) Recv() (T, bool) {
+	m, ok := <-c.ch
+	if ok {
+		c.d.acquire(m.vc)
+		return m.value, true
+	}
+	c.closeMu.Lock()
+	vc := c.closeVC
+	c.closeMu.Unlock()
+	c.d.acquire(vc)
+	return m.value, false
+}
+
+// Close closes the channel; receivers that see it closed acquire the
+// closer's history
+func (c *Chan[T]) Close() {
+	c.closeMu.Lock()
+	c.closeVC = c.d.release()
+	c.closeMu.Unlock()
+	close(c.ch)
+}
+
+// =================================================
+
+// ==================== WAITGROUP ====================
+// WaitGroup is a sync.WaitGroup whose Wait acquires every Done
+type WaitGroup struct {
+	d     *Detector
+	wg    sync.WaitGroup
+	mutex sync.Mutex
+	vc    vclock // Join of every Done so far
+}
+
+// NewWaitGroup creates an empty WaitGroup
+func (d *Detector) NewWaitGroup() *WaitGroup {
+	return &WaitGroup{d: d, vc: vclock{}}
+}
+
+func (w *WaitGroup) Add(n int) { w.wg.Add(n) }
+
+// Done publishes the caller's history to Wait
+func (w *WaitGroup) Done() {
+	snap := w.d.release()
+	w.mutex.Lock()
+	w.vc.join(snap)
+	w.mutex.Unlock()
+	w.wg.Done()
+}
+
+// Wait blocks until the counter is zero, then acquires every Done
+func (w *WaitGroup) Wait() {
+	w.wg.Wait()
+	w.mutex.Lock()
+	vc := w.vc.clone()
+	w.mutex.Unlock()
+	w.d.acquire(vc)
+}
+
+// Go runs f in a new goroutine (through Detector.Go) counted by the group
+func (w *WaitGroup) Go(f func()) {
+	w.Add(1)
+	w.d.Go(func() {
+		defer w.Done()
+		f()
+	})
+}
+
+// ===================================================
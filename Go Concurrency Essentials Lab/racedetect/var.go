@@ -0,0 +1,121 @@
+// Go Concurrency Essentials - Happens-Before Race Detector: Variables
+// Description: Var[T] is a shared variable whose every Load and Store is
+//              checked against the accesses before it
+//
+// A Var remembers its last store and the loads since then, each with the
+// goroutine, that goroutine's logical time and the call stack. A new
+// access races with a remembered one from another goroutine when the
+// current goroutine's clock has not yet reached the remembered time
+
+package racedetect
+
+import "essentials/stacks"
+
+// access is one remembered load or store
+type access struct {
+	g   int64  // Goroutine
+	t   uint64 // Goroutine's own clock entry at the time
+	op  string
+	pcs []uintptr
+}
+
+// raceKey identifies a race by variable and the two call sites
+type raceKey struct {
+	name       string
+	prev, curr uintptr
+}
+
+// Var is a shared variable checked by a Detector. The value itself is kept
+// behind the detector's lock, so the demo program never races for real
+type Var[T any] struct {
+	d     *Detector
+	name  string
+	value T
+	write access           // Last store
+	reads map[int64]access // Loads since the last store, latest per goroutine
+}
+
+// NewVar creates a variable initialised to v, stored by the caller
+func NewVar[T any](d *Detector, name string, v T) *Var[T] {
+	g := stacks.GoID()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return &Var[T]{
+		d:     d,
+		name:  name,
+		value: v,
+		write: access{g: g, t: d.clock(g)[g], op: "store", pcs: stacks.Callers()},
+		reads: make(map[int64]access),
+	}
+}
+
+// Load reads the variable, checking it against the last store
+func (v *Var[T]) Load() T {
+	g, pcs := stacks.GoID(), stacks.Callers()
+	d := v.d
+	d.mutex.Lock()
+	c := d.clock(g)
+	cur := access{g: g, t: c[g], op: "load", pcs: pcs}
+	found := v.check(c, cur, v.write)
+	v.reads[g] = cur
+	value := v.value
+	d.mutex.Unlock()
+
+	d.publish(found)
+	return value
+}
+
+// Store writes the variable, checking it against the last store and every
+// load since
+func (v *Var[T]) Store(value T) {
+	g, pcs := stacks.GoID(), stacks.Callers()
+	d := v.d
+	d.mutex.Lock()
+	c := d.clock(g)
+	cur := access{g: g, t: c[g], op: "store", pcs: pcs}
+	found := v.check(c, cur, v.write)
+	for _, r := range v.reads {
+		found = append(found, v.check(c, cur, r)...)
+	}
+	v.write = cur
+	clear(v.reads)
+	v.value = value
+	d.mutex.Unlock()
+
+	d.publish(found)
+}
+
+// check reports prev and cur as a race if prev is by another goroutine and
+// not yet in the current goroutine's clock c (caller holds d.mutex)
+func (v *Var[T]) check(c vclock, cur, prev access) []Report {
+	if prev.g == cur.g || prev.t <= c[prev.g] {
+		return nil // Same goroutine, or prev happens before cur
+	}
+	key := raceKey{name: v.name, prev: first(prev.pcs), curr: first(cur.pcs)}
+	if v.d.seen[key] {
+		return nil
+	}
+	v.d.seen[key] = true
+	r := Report{
+		Var:      v.name,
+		Previous: Access{Goroutine: prev.g, Op: prev.op, Stack: stacks.Frames(prev.pcs)},
+		Current:  Access{Goroutine: cur.g, Op: cur.op, Stack: stacks.Frames(cur.pcs)},
+	}
+	v.d.reports = append(v.d.reports, r)
+	return []Report{r}
+}
+
+// first returns the innermost pc, or 0
+func first(pcs []uintptr) uintptr {
+	if len(pcs) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// publish hands reports to the callback, outside the detector's lock
+func (d *Detector) publish(reports []Report) {
+	for _, r := range reports {
+		d.report(r)
+	}
+}
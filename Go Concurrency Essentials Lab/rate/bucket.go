@@ -0,0 +1,114 @@
+// Go Concurrency Essentials - Token Bucket
+// Description: Rate limiter holding up to burst tokens that refill at a
+//              fixed rate; every request spends one token
+//
+// A request arriving with no token available books the moment the next
+// token will exist, so the bucket can go into debt and callers are served
+// in booking order
+
+package rate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ==================== TOKEN BUCKET ====================
+// TokenBucket allows bursts of up to burst requests and rate per second
+// on average
+type TokenBucket struct {
+	mutex  sync.Mutex
+	clock  Clock
+	rate   float64   // Tokens added per second
+	burst  float64   // Bucket size
+	tokens float64   // Tokens available (negative: booked ahead)
+	last   time.Time // When tokens was last brought up to date
+}
+
+// NewTokenBucket creates a full token bucket
+// Parameters:
+//   - rate: Tokens per second (must be > 0, panics otherwise)
+//   - burst: Bucket size (at least 1)
+//   - clock: Time source (nil means Real)
+//
+// Returns:
+//   - Pointer to initialized bucket
+func NewTokenBucket(rate float64, burst int, clock Clock) *TokenBucket {
+	checkRate(rate)
+	if clock == nil {
+		clock = Real
+	}
+	b := float64(max(burst, 1))
+	return &TokenBucket{clock: clock, rate: rate, burst: b, tokens: b, last: clock.Now()}
+}
+
+// checkRate panics unless rate is positive: a zero rate never refills and
+// a negative or NaN one corrupts every delay computed from it
+func checkRate(rate float64) {
+	if !(rate > 0) {
+		panic(fmt.Sprintf("rate: rate must be > 0, got %v", rate))
+	}
+}
+
+// refill adds the tokens earned since last. Caller holds b.mutex
+func (b *TokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// Reserve spends a token, booking a future one if none is available
+func (b *TokenBucket) Reserve() *Reservation {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := b.clock.Now()
+	b.refill(now)
+	b.tokens--
+	at := now
+	if b.tokens < 0 {
+		at = now.Add(time.Duration(-b.tokens / b.rate * float64(time.Second)))
+	}
+	return &Reservation{ok: true, at: at, clock: b.clock, cancel: b.restore}
+}
+
+// restore gives back a token booked for a time still in the future
+func (b *TokenBucket) restore(at time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := b.clock.Now()
+	if !at.After(now) {
+		return // Already used
+	}
+	b.refill(now)
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// Allow spends a token only if one is available now
+func (b *TokenBucket) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(b.clock.Now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b.Reserve())
+}
+
+// Tokens returns the tokens available now (negative when booked ahead)
+func (b *TokenBucket) Tokens() float64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(b.clock.Now())
+	return b.tokens
+}
+
+// ======================================================
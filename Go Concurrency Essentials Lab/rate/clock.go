@@ -0,0 +1,93 @@
+// Go Concurrency Essentials - Rate Limiter Clocks
+// Description: Time source used by the rate limiters: the real clock, or a
+//              virtual clock that only moves when told to, so limiter
+//              behaviour can be checked exactly and instantly
+
+package rate
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time and timers
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the wall clock
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// ==================== VIRTUAL CLOCK ====================
+// VirtualClock is a Clock whose time only changes through Advance
+type VirtualClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []timer // Pending After calls
+}
+
+// timer is a pending After call
+type timer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewVirtualClock creates a virtual clock reading start
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start}
+}
+
+// Now returns the virtual time
+func (c *VirtualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives once the clock reaches now+d
+func (c *VirtualClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, timer{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every timer now due,
+// earliest first
+func (c *VirtualClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	sort.Slice(c.timers, func(i, j int) bool { return c.timers[i].at.Before(c.timers[j].at) })
+	fired := 0
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			break
+		}
+		t.ch <- t.at
+		fired++
+	}
+	c.timers = c.timers[fired:]
+}
+
+// Pending returns the number of timers not yet fired, so a driver can wait
+// until every goroutine under test is blocked before advancing
+func (c *VirtualClock) Pending() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+// =======================================================
@@ -0,0 +1,94 @@
+// Go Concurrency Essentials - Leaky Bucket
+// Description: Rate limiter that lets requests out one at a time at a fixed
+//              interval, queueing up to capacity requests behind the current
+//              one and refusing the rest
+//
+// Unlike the token bucket it never saves up idle time, so output is evenly
+// spaced even right after a quiet period
+
+package rate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ==================== LEAKY BUCKET ====================
+// LeakyBucket spaces requests exactly 1/rate apart
+type LeakyBucket struct {
+	mutex    sync.Mutex
+	clock    Clock
+	interval time.Duration // Gap between requests
+	capacity int           // Requests that may queue behind the next slot
+	next     time.Time     // Earliest time the next request may go
+}
+
+// NewLeakyBucket creates an empty leaky bucket
+// Parameters:
+//   - rate: Requests per second (must be > 0, panics otherwise)
+//   - capacity: Requests allowed to queue (0 means Wait never queues)
+//   - clock: Time source (nil means Real)
+//
+// Returns:
+//   - Pointer to initialized bucket
+func NewLeakyBucket(rate float64, capacity int, clock Clock) *LeakyBucket {
+	checkRate(rate)
+	if clock == nil {
+		clock = Real
+	}
+	return &LeakyBucket{
+		clock:    clock,
+		interval: time.Duration(float64(time.Second) / rate),
+		capacity: max(capacity, 0),
+		next:     clock.Now(),
+	}
+}
+
+// Reserve books the next free slot, or fails if the queue is full
+func (b *LeakyBucket) Reserve() *Reservation {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := b.clock.Now()
+	at := b.next
+	if at.Before(now) {
+		at = now // Idle time is not saved up
+	}
+	// Slots already booked ahead of this one = queue length
+	if at.Sub(now) > time.Duration(b.capacity)*b.interval {
+		return &Reservation{ok: false, clock: b.clock}
+	}
+	b.next = at.Add(b.interval)
+	return &Reservation{ok: true, at: at, clock: b.clock, cancel: b.restore}
+}
+
+// restore frees a booked slot if it is still the last one in the queue
+// (an earlier slot cannot be handed back without moving later ones)
+func (b *LeakyBucket) restore(at time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if at.After(b.clock.Now()) && b.next.Equal(at.Add(b.interval)) {
+		b.next = at
+	}
+}
+
+// Allow lets the request through only if the slot is free now
+func (b *LeakyBucket) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	now := b.clock.Now()
+	if b.next.After(now) {
+		return false
+	}
+	b.next = now.Add(b.interval)
+	return true
+}
+
+// Wait blocks until the request's slot arrives or ctx is done
+// Returns:
+//   - nil, ctx.Err(), or ErrFull when the queue has no room
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b.Reserve())
+}
+
+// ======================================================
@@ -0,0 +1,120 @@
+// Go Concurrency Essentials - Rate Limiters
+// Description: Limits how fast tasks start, as opposed to how many run at
+//              once (the semaphore's job). Two algorithms share one API:
+//              - TokenBucket: tokens refill at a steady rate up to a burst
+//                size, so idle time is saved up for short bursts
+//              - LeakyBucket: requests leave at a fixed interval, queueing
+//                up to a capacity, so the output never bursts
+//
+// Throttle combines a rate limiter with a concurrency semaphore, so a pool
+// can be bounded by both at the same time
+
+package rate
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFull is returned when a leaky bucket's queue has no room
+var ErrFull = errors.New("rate: queue is full")
+
+// Limiter is implemented by TokenBucket and LeakyBucket
+type Limiter interface {
+	Wait(ctx context.Context) error // Block until the request may proceed
+	Allow() bool                    // Proceed now or not at all
+	Reserve() *Reservation          // Book a slot and learn how long to wait
+}
+
+// ==================== RESERVATION ====================
+// Reservation is a booked start time returned by Reserve
+type Reservation struct {
+	ok     bool
+	at     time.Time          // When the request may proceed
+	clock  Clock              // Clock the limiter runs on
+	cancel func(at time.Time) // Gives the booking back, if still possible
+}
+
+// OK reports whether the limiter could book a time at all
+func (r *Reservation) OK() bool { return r.ok }
+
+// Delay returns how long to wait before proceeding (0 if it may go now)
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return time.Duration(1<<63 - 1)
+	}
+	return max(r.at.Sub(r.clock.Now()), 0)
+}
+
+// Cancel returns the booking so later requests can use it. It has no
+// effect once the booked time has passed
+func (r *Reservation) Cancel() {
+	if r.ok && r.cancel != nil {
+		r.cancel(r.at)
+		r.cancel = nil
+	}
+}
+
+// wait blocks until r's time arrives or ctx is done, cancelling r if ctx
+// ends first
+func wait(ctx context.Context, r *Reservation) error {
+	if !r.OK() {
+		return ErrFull
+	}
+	d := r.Delay()
+	if d == 0 {
+		return nil
+	}
+	select {
+	case <-r.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// =====================================================
+
+// ==================== THROTTLE ====================
+// Semaphore is a concurrency limit (it matches pool.Limiter)
+type Semaphore interface {
+	Acquire(ctx context.Context) error
+	Release()
+}
+
+// throttled is a Semaphore that also waits on a rate limiter
+type throttled struct {
+	sem Semaphore
+	lim Limiter
+}
+
+// Throttle bounds both concurrency and start rate
+// The slot is taken first and the rate wait happens while holding it:
+// a slot sits idle for a moment, but starts are then spaced exactly by
+// the limiter no matter how slots free up
+// Parameters:
+//   - sem: Concurrency limit
+//   - lim: Start-rate limit
+//
+// Returns:
+//   - Semaphore usable with pool.NewWithLimiter
+func Throttle(sem Semaphore, lim Limiter) Semaphore {
+	return throttled{sem: sem, lim: lim}
+}
+
+func (t throttled) Acquire(ctx context.Context) error {
+	if err := t.sem.Acquire(ctx); err != nil {
+		return err
+	}
+	if err := t.lim.Wait(ctx); err != nil {
+		t.sem.Release() // Never keep a slot we will not use
+		return err
+	}
+	return nil
+}
+
+func (t throttled) Release() { t.sem.Release() }
+
+// ==================================================
@@ -0,0 +1,149 @@
+// Go Concurrency Essentials - Rate Limiter Checks
+// Description: Drives both rate limiters on a virtual clock and checks the
+//              exact time every request is admitted. No real time passes,
+//              so the results are the same on every run. Exits 1 if any
+//              check fails
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"essentials/rate"
+)
+
+var failed bool
+
+// expect prints one check and records a failure
+func expect(cond bool, format string, args ...any) {
+	status := "ok  "
+	if !cond {
+		status = "FAIL"
+		failed = true
+	}
+	fmt.Printf("  %s "+format+"\n", append([]any{status}, args...)...)
+}
+
+// delays reserves n times and returns each reservation's delay
+func delays(lim rate.Limiter, n int) []time.Duration {
+	out := make([]time.Duration, n)
+	for i := range out {
+		r := lim.Reserve()
+		if !r.OK() {
+			out[i] = -1
+			continue
+		}
+		out[i] = r.Delay()
+	}
+	return out
+}
+
+// equal compares delay lists
+func equal(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// settle waits until n goroutines are blocked on the virtual clock
+func settle(clock *rate.VirtualClock, n int) {
+	for clock.Pending() < n {
+		runtime.Gosched()
+	}
+}
+
+// ==================== TOKEN BUCKET ====================
+func checkTokenBucket(start time.Time) {
+	fmt.Println("Token bucket: 10/s, burst 3")
+	clock := rate.NewVirtualClock(start)
+	tb := rate.NewTokenBucket(10, 3, clock)
+
+	ms := time.Millisecond
+	got := delays(tb, 5)
+	want := []time.Duration{0, 0, 0, 100 * ms, 200 * ms}
+	expect(equal(got, want), "burst of 3 then 100ms apart: %v", got)
+	expect(!tb.Allow(), "Allow refuses while in debt")
+
+	clock.Advance(300 * ms)
+	expect(tb.Allow(), "Allow succeeds once the debt is repaid")
+
+	clock.Advance(10 * time.Second)
+	expect(tb.Tokens() == 3, "idle time refills only up to the burst (%.0f tokens)", tb.Tokens())
+}
+
+// ==================== LEAKY BUCKET ====================
+func checkLeakyBucket(start time.Time) {
+	fmt.Println("Leaky bucket: 10/s, queue 2")
+	clock := rate.NewVirtualClock(start)
+	lb := rate.NewLeakyBucket(10, 2, clock)
+
+	ms := time.Millisecond
+	got := delays(lb, 4)
+	want := []time.Duration{0, 100 * ms, 200 * ms, -1}
+	expect(equal(got, want), "evenly spaced, 4th refused (queue full): %v", got)
+	expect(!lb.Allow(), "Allow refuses while requests are queued")
+	expect(lb.Wait(context.Background()) == rate.ErrFull, "Wait returns ErrFull when the queue is full")
+
+	clock.Advance(10 * time.Second)
+	got = delays(lb, 3)
+	want = []time.Duration{0, 100 * ms, 200 * ms}
+	expect(equal(got, want), "idle time is not saved up for a burst: %v", got)
+}
+
+// ==================== WAIT AND CANCEL ====================
+func checkWait(start time.Time) {
+	fmt.Println("Wait on a virtual clock: 10/s, burst 1, 3 waiters")
+	clock := rate.NewVirtualClock(start)
+	tb := rate.NewTokenBucket(10, 1, clock)
+
+	admitted := make(chan time.Duration, 3)
+	for range 3 {
+		go func() {
+			if tb.Wait(context.Background()) == nil {
+				admitted <- clock.Now().Sub(start)
+			}
+		}()
+	}
+	// First waiter takes the token; the other two book 100ms and 200ms
+	first := <-admitted
+	settle(clock, 2)
+	clock.Advance(100 * time.Millisecond)
+	second := <-admitted
+	settle(clock, 1)
+	clock.Advance(100 * time.Millisecond)
+	third := <-admitted
+	expect(first == 0 && second == 100*time.Millisecond && third == 200*time.Millisecond,
+		"admitted at %v, %v, %v", first, second, third)
+
+	// A cancelled Wait gives its booked token back
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- tb.Wait(ctx) }()
+	settle(clock, 1)
+	before := tb.Tokens()
+	cancel()
+	err := <-errc
+	expect(err == context.Canceled, "cancelled Wait returns %v", err)
+	expect(tb.Tokens() == before+1, "cancelled Wait returns its token (%.0f -> %.0f)", before, tb.Tokens())
+}
+
+// main runs every check
+func main() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	checkTokenBucket(start)
+	checkLeakyBucket(start)
+	checkWait(start)
+	if failed {
+		os.Exit(1)
+	}
+}
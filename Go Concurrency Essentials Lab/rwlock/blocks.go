@@ -0,0 +1,137 @@
+// Go Concurrency Essentials - Readers-Writers Building Blocks
+// Description: The two pieces every lock in this package is made from,
+//              as in The Little Book of Semaphores:
+//              - semaphore: the buffered-channel semaphore from semaphore.go
+//              - lightswitch: the first one into a room locks it for the
+//                whole group, the last one out unlocks it
+//
+// The lightswitch also supports handing the room over between a writer
+// and the reader group, which is what upgrade and downgrade need
+
+package rwlock
+
+import "sync"
+
+// ==================== SEMAPHORE ====================
+// semaphore is a binary semaphore: the channel holds the free permit
+type semaphore chan struct{}
+
+// newSemaphore creates a binary semaphore with its permit available
+func newSemaphore() semaphore {
+	s := make(semaphore, 1)
+	s <- struct{}{}
+	return s
+}
+
+func (s semaphore) wait()   { <-s }
+func (s semaphore) signal() { s <- struct{}{} }
+
+// tryWait takes the permit only if it is free
+func (s semaphore) tryWait() bool {
+	select {
+	case <-s:
+		return true
+	default:
+		return false
+	}
+}
+
+// ===================================================
+
+// ==================== LIGHTSWITCH ====================
+// lightswitch lets a group share a room: the first member in waits for the
+// room, later members walk in, and the last member out releases it
+type lightswitch struct {
+	mutex sync.Mutex // Serialises lock and unlock, as in the classic version
+
+	// state guards count and waiting separately from mutex, because the first
+	// member holds mutex while it waits for the room and a downgrading writer
+	// must still be able to join
+	state   sync.Mutex
+	count   int           // Members in (or entering) the room
+	waiting bool          // The first member is blocked waiting for the room
+	handoff chan struct{} // Gives the room to a waiting first member
+}
+
+// newLightswitch creates an empty lightswitch
+func newLightswitch() *lightswitch {
+	return &lightswitch{handoff: make(chan struct{}, 1)}
+}
+
+// lock enters the group, taking room if the group is empty
+func (l *lightswitch) lock(room semaphore) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.state.Lock()
+	l.count++
+	first := l.count == 1
+	l.waiting = first
+	l.state.Unlock()
+	if first {
+		select {
+		case <-room:
+		case <-l.handoff: // A downgrading writer passed its room to the group
+		}
+		l.state.Lock()
+		l.waiting = false
+		l.state.Unlock()
+	}
+}
+
+// tryLock is lock without waiting
+func (l *lightswitch) tryLock(room semaphore) bool {
+	if !l.mutex.TryLock() {
+		return false
+	}
+	defer l.mutex.Unlock()
+	l.state.Lock()
+	defer l.state.Unlock()
+	if l.count == 0 && !room.tryWait() {
+		return false
+	}
+	l.count++
+	return true
+}
+
+// unlock leaves the group, releasing room if this was the last member
+func (l *lightswitch) unlock(room semaphore) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.state.Lock()
+	l.count--
+	last := l.count == 0
+	l.state.Unlock()
+	if last {
+		room.signal()
+	}
+}
+
+// join adds a member who already holds room (a downgrading writer). If a
+// first member is waiting for the room, the room is handed to it
+func (l *lightswitch) join() {
+	l.state.Lock()
+	defer l.state.Unlock()
+	l.count++
+	if l.waiting {
+		l.waiting = false
+		l.handoff <- struct{}{}
+	}
+}
+
+// tryLeaveHolding removes the only member without releasing room, so that
+// member keeps the room for itself (an upgrading reader)
+// Returns:
+//   - false, changing nothing, if other members are in the room
+func (l *lightswitch) tryLeaveHolding() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.state.Lock()
+	defer l.state.Unlock()
+	if l.count != 1 {
+		return false
+	}
+	l.count = 0
+	return true
+}
+
+// =====================================================
@@ -0,0 +1,173 @@
+// Go Concurrency Essentials - Readers-Writers Locks
+// Description: Three readers-writers locks with different preference
+//              policies, all built from semaphores and lightswitches:
+//              - ReaderPreference: readers keep the room while any reader is
+//                in it; writers can starve
+//              - WriterPreference: a waiting writer stops new readers;
+//                readers can starve
+//              - Fair: everyone passes a turnstile in arrival order, so a
+//                waiting writer holds back later readers and nobody starves
+//
+// Every lock can be downgraded from write to read without letting a writer
+// in between. Upgrading is only offered as TryUpgrade: two readers that
+// both wait to become the writer would each wait for the other to leave
+
+package rwlock
+
+import "fmt"
+
+// Policy selects which side a lock favours
+type Policy int
+
+const (
+	ReaderPreference Policy = iota
+	WriterPreference
+	Fair
+)
+
+// Policies lists every policy, in report order
+var Policies = []Policy{ReaderPreference, WriterPreference, Fair}
+
+func (p Policy) String() string {
+	switch p {
+	case ReaderPreference:
+		return "reader-pref"
+	case WriterPreference:
+		return "writer-pref"
+	case Fair:
+		return "fair"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// RWLock is a readers-writers lock
+type RWLock interface {
+	RLock()
+	RUnlock()
+	Lock()
+	Unlock()
+
+	// TryUpgrade turns the caller's read lock into the write lock if it is
+	// the only reader. On false the caller still holds its read lock
+	TryUpgrade() bool
+
+	// Downgrade turns the caller's write lock into a read lock; no writer
+	// can get in between
+	Downgrade()
+
+	Policy() Policy
+}
+
+// New creates an unlocked RWLock with the given policy
+func New(p Policy) RWLock {
+	switch p {
+	case ReaderPreference:
+		return &readerPref{roomEmpty: newSemaphore(), readers: newLightswitch()}
+	case WriterPreference:
+		return &writerPref{
+			noReaders: newSemaphore(),
+			noWriters: newSemaphore(),
+			readers:   newLightswitch(),
+			writers:   newLightswitch(),
+		}
+	case Fair:
+		return &fair{turnstile: newSemaphore(), roomEmpty: newSemaphore(), readers: newLightswitch()}
+	}
+	panic(fmt.Sprintf("rwlock: unknown policy %d", int(p)))
+}
+
+// ==================== READER PREFERENCE ====================
+// readerPref: the first reader locks the room for all readers, and a writer
+// needs the empty room. As long as readers overlap, writers wait
+type readerPref struct {
+	roomEmpty semaphore
+	readers   *lightswitch
+}
+
+func (l *readerPref) RLock()   { l.readers.lock(l.roomEmpty) }
+func (l *readerPref) RUnlock() { l.readers.unlock(l.roomEmpty) }
+func (l *readerPref) Lock()    { l.roomEmpty.wait() }
+func (l *readerPref) Unlock()  { l.roomEmpty.signal() }
+
+func (l *readerPref) TryUpgrade() bool { return l.readers.tryLeaveHolding() }
+func (l *readerPref) Downgrade()       { l.readers.join() }
+func (l *readerPref) Policy() Policy   { return ReaderPreference }
+
+// ==================== WRITER PREFERENCE ====================
+// writerPref: readers must pass noReaders, which the first waiting writer
+// takes for the whole writer group; writers then queue on noWriters until
+// the readers already inside leave
+type writerPref struct {
+	noReaders semaphore // Held by the writer group: no new readers
+	noWriters semaphore // Held by the reader group or the active writer
+	readers   *lightswitch
+	writers   *lightswitch
+}
+
+func (l *writerPref) RLock() {
+	l.noReaders.wait()
+	l.readers.lock(l.noWriters)
+	l.noReaders.signal()
+}
+
+func (l *writerPref) RUnlock() { l.readers.unlock(l.noWriters) }
+
+func (l *writerPref) Lock() {
+	l.writers.lock(l.noReaders)
+	l.noWriters.wait()
+}
+
+func (l *writerPref) Unlock() {
+	l.noWriters.signal()
+	l.writers.unlock(l.noReaders)
+}
+
+func (l *writerPref) TryUpgrade() bool {
+	// Join the writers first so no new reader gets in, then take over the
+	// reader group's hold on noWriters
+	if !l.writers.tryLock(l.noReaders) {
+		return false
+	}
+	if !l.readers.tryLeaveHolding() {
+		l.writers.unlock(l.noReaders)
+		return false
+	}
+	return true
+}
+
+func (l *writerPref) Downgrade() {
+	l.readers.join() // The reader group takes over noWriters
+	l.writers.unlock(l.noReaders)
+}
+
+func (l *writerPref) Policy() Policy { return WriterPreference }
+
+// ==================== FAIR ====================
+// fair: readers and writers pass one turnstile in arrival order. A writer
+// keeps the turnstile shut while it waits for the room to empty, so later
+// readers queue behind it instead of overtaking
+type fair struct {
+	turnstile semaphore
+	roomEmpty semaphore
+	readers   *lightswitch
+}
+
+func (l *fair) RLock() {
+	l.turnstile.wait()
+	l.turnstile.signal()
+	l.readers.lock(l.roomEmpty)
+}
+
+func (l *fair) RUnlock() { l.readers.unlock(l.roomEmpty) }
+
+func (l *fair) Lock() {
+	l.turnstile.wait()
+	l.roomEmpty.wait()
+	l.turnstile.signal()
+}
+
+func (l *fair) Unlock() { l.roomEmpty.signal() }
+
+func (l *fair) TryUpgrade() bool { return l.readers.tryLeaveHolding() }
+func (l *fair) Downgrade()       { l.readers.join() }
+func (l *fair) Policy() Policy   { return Fair }
@@ -0,0 +1,249 @@
+// Go Concurrency Essentials - Readers-Writers Policy Simulation
+// Description: Runs the same mix of busy readers and occasional writers
+//              against each rwlock policy and reports reader throughput and
+//              writer latency, then checks upgrade and downgrade
+//
+// Readers hold the lock for a while and come straight back, so they almost
+// always overlap: under reader preference writers wait until the readers
+// stop, under writer preference readers stall behind every writer, and the
+// fair lock sits in between. Every critical section also checks that no
+// reader is in with a writer and that no two writers are in together.
+// Exits 1 if a check fails
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"essentials/rwlock"
+)
+
+// config is the simulated workload
+type config struct {
+	readers, writers int
+	duration         time.Duration
+	readHold         time.Duration // Time a reader holds the lock
+	writeHold        time.Duration // Time a writer holds the lock
+	writeEvery       time.Duration // Pause between one writer's writes
+}
+
+// result is one policy's run
+type result struct {
+	reads      int64
+	latencies  []time.Duration // Writer wait for Lock
+	violations int64
+}
+
+// shared is the protected data: writers keep a == b
+type shared struct {
+	a, b int
+}
+
+// simulate runs cfg against lock until cfg.duration is up; writers still
+// waiting then are let in once the readers stop
+func simulate(lock rwlock.RWLock, cfg config) result {
+	var res result
+	var data shared
+	var readersIn, writersIn, reads, violations atomic.Int64
+	var mu sync.Mutex // Guards res.latencies
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range cfg.readers {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				lock.RLock()
+				readersIn.Add(1)
+				if writersIn.Load() != 0 || data.a != data.b {
+					violations.Add(1)
+				}
+				time.Sleep(cfg.readHold)
+				readersIn.Add(-1)
+				lock.RUnlock()
+				reads.Add(1)
+			}
+		})
+	}
+	for range cfg.writers {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				time.Sleep(cfg.writeEvery)
+				start := time.Now()
+				lock.Lock()
+				wait := time.Since(start)
+				if writersIn.Add(1) != 1 || readersIn.Load() != 0 {
+					violations.Add(1)
+				}
+				data.a++
+				time.Sleep(cfg.writeHold)
+				data.b++
+				writersIn.Add(-1)
+				lock.Unlock()
+
+				mu.Lock()
+				res.latencies = append(res.latencies, wait)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	res.reads = reads.Load()
+	res.violations = violations.Load()
+	return res
+}
+
+// percentile returns the p-th percentile (0-100) of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[min(len(sorted)-1, len(sorted)*p/100)]
+}
+
+// within reports whether f returns within d
+func within(d time.Duration, f func()) bool {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// checkUpgrades exercises TryUpgrade and Downgrade on a fresh lock
+// Returns:
+//   - Descriptions of every failed check
+func checkUpgrades(p rwlock.Policy) []string {
+	var problems []string
+	fail := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	const quick = 100 * time.Millisecond // Long enough for anything that should not block
+	const blocked = 20 * time.Millisecond
+
+	// A lone reader upgrades, writes, and downgrades
+	l := rwlock.New(p)
+	l.RLock()
+	if !l.TryUpgrade() {
+		fail("lone reader could not upgrade")
+	}
+	if within(blocked, l.RLock) {
+		fail("reader got in while upgraded")
+	} // The stuck RLock is let in by the downgrade below
+	l.Downgrade()
+	time.Sleep(blocked) // Let the stuck reader in
+	writerIn := make(chan struct{})
+	go func() {
+		l.Lock()
+		close(writerIn)
+	}()
+	select {
+	case <-writerIn:
+		fail("writer got in while downgraded readers held the lock")
+	case <-time.After(blocked):
+	}
+	l.RUnlock()
+	l.RUnlock()
+	select {
+	case <-writerIn:
+		l.Unlock()
+	case <-time.After(quick):
+		fail("writer not let in after readers left")
+	}
+
+	// With a second reader, upgrading must fail and keep the read lock
+	l = rwlock.New(p)
+	l.RLock()
+	l.RLock()
+	if l.TryUpgrade() {
+		fail("upgrade succeeded with two readers")
+	}
+	l.RUnlock()
+	l.RUnlock()
+	if !within(quick, func() { l.Lock(); l.Unlock() }) {
+		fail("lock not free after failed upgrade")
+	}
+
+	// A reader already waiting when the writer downgrades is let in with it
+	l = rwlock.New(p)
+	l.Lock()
+	readerIn := make(chan struct{})
+	go func() {
+		l.RLock()
+		close(readerIn)
+	}()
+	time.Sleep(blocked) // Reader is now waiting for the writer
+	l.Downgrade()
+	select {
+	case <-readerIn:
+	case <-time.After(quick):
+		fail("waiting reader not let in by downgrade")
+	}
+	l.RUnlock()
+	l.RUnlock()
+	if !within(quick, func() { l.Lock(); l.Unlock() }) {
+		fail("lock not free after downgrade")
+	}
+	return problems
+}
+
+// main simulates every policy and checks upgrade and downgrade
+func main() {
+	var cfg config
+	flag.IntVar(&cfg.readers, "readers", 8, "reader goroutines")
+	flag.IntVar(&cfg.writers, "writers", 2, "writer goroutines")
+	flag.DurationVar(&cfg.duration, "duration", 500*time.Millisecond, "simulated time per policy")
+	flag.DurationVar(&cfg.readHold, "read-hold", 200*time.Microsecond, "time each read holds the lock")
+	flag.DurationVar(&cfg.writeHold, "write-hold", 200*time.Microsecond, "time each write holds the lock")
+	flag.DurationVar(&cfg.writeEvery, "write-every", 5*time.Millisecond, "pause between a writer's writes")
+	flag.Parse()
+
+	failed := false
+	fmt.Printf("%d readers, %d writers, %v per policy\n\n", cfg.readers, cfg.writers, cfg.duration)
+	fmt.Printf("%-12s %9s %7s %12s %12s %12s  %s\n",
+		"policy", "reads/s", "writes", "write p50", "write p99", "write max", "exclusion")
+	for _, p := range rwlock.Policies {
+		res := simulate(rwlock.New(p), cfg)
+		slices.Sort(res.latencies)
+		status := "ok"
+		if res.violations > 0 {
+			status = fmt.Sprintf("FAIL (%d)", res.violations)
+			failed = true
+		}
+		fmt.Printf("%-12s %9.0f %7d %12v %12v %12v  %s\n",
+			p, float64(res.reads)/cfg.duration.Seconds(), len(res.latencies),
+			percentile(res.latencies, 50).Round(time.Microsecond),
+			percentile(res.latencies, 99).Round(time.Microsecond),
+			percentile(res.latencies, 100).Round(time.Microsecond), status)
+	}
+
+	fmt.Println("\nUpgrade and downgrade:")
+	for _, p := range rwlock.Policies {
+		problems := checkUpgrades(p)
+		if len(problems) == 0 {
+			fmt.Printf("  %-12s ok\n", p)
+			continue
+		}
+		failed = true
+		for _, problem := range problems {
+			fmt.Printf("  %-12s FAIL: %s\n", p, problem)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,202 @@
+// Go Concurrency Essentials - Weighted Semaphore Example (Output)
+// Description: Structured output formats for sem-ex results
+//              text, json, csv and ndjson, written either all at once
+//              after the workers finish or streamed as results complete
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"essentials/collatz"
+)
+
+// record is one output row: a Collatz result plus how it was computed
+type record struct {
+	Input        int           `json:"input"`
+	Steps        int           `json:"steps"`
+	StoppingTime int           `json:"stopping_time"`
+	Peak         *big.Int      `json:"peak"`
+	Worker       int           `json:"worker"`
+	Duration     time.Duration `json:"duration_ns"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// newRecord combines a result with the worker and time that produced it
+func newRecord(r collatz.Result, worker int, d time.Duration) record {
+	rec := record{
+		Input:        r.Input,
+		Steps:        r.Steps,
+		StoppingTime: r.StoppingTime,
+		Peak:         r.Peak,
+		Worker:       worker,
+		Duration:     d,
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	}
+	return rec
+}
+
+// writeSummary prints the plain text output: every step count on one line,
+// then the failed inputs
+// Parameters:
+//   - w: Destination
+//   - start: First input
+//   - results: One result per input, in input order
+//
+// Returns:
+//   - Any error writing to w
+func writeSummary(w io.Writer, start int, results collatz.Results) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nCollatz steps for numbers %d to %d:\n", start, start+len(results)-1)
+	fmt.Fprintln(&b, results.Steps())
+
+	if failed := results.Failed(); len(failed) > 0 {
+		fmt.Fprintf(&b, "\n%d succeeded, %d failed (shown as -1):\n", len(results.Succeeded()), len(failed))
+		for _, r := range failed {
+			fmt.Fprintf(&b, "  %d: %v\n", r.Input, r.Err)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ==================== WRITERS ====================
+// writer emits records one at a time; Close finishes the document
+type writer interface {
+	Write(rec record) error
+	Close() error
+}
+
+// formats lists the accepted -format values
+var formats = []string{"text", "json", "csv", "ndjson"}
+
+// newWriter creates a writer for the given format
+// Parameters:
+//   - format: One of formats
+//   - w: Destination
+//
+// Returns:
+//   - The writer, or an error for an unknown format
+func newWriter(format string, w io.Writer) (writer, error) {
+	switch format {
+	case "text":
+		return &textWriter{w: w}, nil
+	case "json":
+		return &jsonWriter{w: w}, nil
+	case "csv":
+		return newCSVWriter(w)
+	case "ndjson":
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q (want one of %v)", format, formats)
+}
+
+// textWriter prints one human-readable line per record
+type textWriter struct {
+	w io.Writer
+}
+
+func (t *textWriter) Write(rec record) error {
+	if rec.Error != "" {
+		_, err := fmt.Fprintf(t.w, "n=%d worker=%d duration=%v error=%q\n",
+			rec.Input, rec.Worker, rec.Duration, rec.Error)
+		return err
+	}
+	_, err := fmt.Fprintf(t.w, "n=%d steps=%d stopping=%d peak=%s worker=%d duration=%v\n",
+		rec.Input, rec.Steps, rec.StoppingTime, rec.Peak, rec.Worker, rec.Duration)
+	return err
+}
+
+func (t *textWriter) Close() error { return nil }
+
+// jsonWriter writes a JSON array incrementally, so it stays valid JSON
+// while still streaming
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) Write(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	j.count++
+	_, err = fmt.Fprintf(j.w, "%s%s", sep, data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if j.count == 0 {
+		_, err := io.WriteString(j.w, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+// ndjsonWriter writes one JSON object per line
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(rec record) error { return n.enc.Encode(rec) }
+func (n *ndjsonWriter) Close() error           { return nil }
+
+// csvWriter writes a header row followed by one row per record
+// Rows are flushed immediately so streaming consumers see them at once
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	c := &csvWriter{w: csv.NewWriter(w)}
+	header := []string{"input", "steps", "stopping_time", "peak", "worker", "duration_ns", "error"}
+	if err := c.w.Write(header); err != nil {
+		return nil, err
+	}
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvWriter) Write(rec record) error {
+	peak := ""
+	if rec.Peak != nil {
+		peak = rec.Peak.String()
+	}
+	err := c.w.Write([]string{
+		strconv.Itoa(rec.Input),
+		strconv.Itoa(rec.Steps),
+		strconv.Itoa(rec.StoppingTime),
+		peak,
+		strconv.Itoa(rec.Worker),
+		strconv.FormatInt(rec.Duration.Nanoseconds(), 10),
+		rec.Error,
+	})
+	if err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
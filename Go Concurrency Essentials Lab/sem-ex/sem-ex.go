@@ -1,108 +1,156 @@
 // Go Concurrency Essentials - Weighted Semaphore Example
 // Description: Demonstrates worker pool pattern using weighted semaphores
-//              from golang.org/x/sync/semaphore package
+//              (the in-repo weighted package), wrapped by pool.Pool
 //
 // This example computes Collatz conjecture steps for numbers 1-64
-// using a limited number of concurrent workers
+// using a limited number of concurrent workers. Each input reports its
+// own success or failure, so one bad input does not lose the others
 
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"runtime"
+	"time"
 
-	"golang.org/x/sync/semaphore"
+	"essentials/collatz"
+	"essentials/pool"
 )
 
 // main demonstrates worker pool using weighted semaphore
 // Pattern: Limit concurrent goroutines to number of CPU cores
 func main() {
+	start := flag.Int("start", 1, "first input")
+	count := flag.Int("count", 64, "number of consecutive inputs")
+	useBig := flag.Bool("big", false, "use the math/big engine (no overflow)")
+	format := flag.String("format", "text", fmt.Sprintf("output format %v", formats))
+	stream := flag.Bool("stream", false, "emit each result as soon as it completes")
+	flag.Parse()
+	if *count < 0 {
+		fmt.Fprintf(os.Stderr, "bad -count: %d, must not be negative\n", *count)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Choose the Collatz engine
+	compute := collatz.Compute
+	if *useBig {
+		compute = collatz.ComputeBig
+	}
+
+	// Plain text without streaming keeps the original summary output;
+	// everything else goes through a structured writer
+	var out writer
+	structured := *format != "text" || *stream
+	if structured {
+		w, err := newWriter(*format, os.Stdout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out = w
+	}
+	banner := io.Writer(os.Stdout)
+	if structured {
+		banner = os.Stderr // Keep stdout machine-readable
+	}
+
 	ctx := context.TODO()
 
 	var (
 		// Limit workers to number of available CPU cores
 		maxWorkers = runtime.GOMAXPROCS(0)
-		// Weighted semaphore with capacity = maxWorkers
-		sem = semaphore.NewWeighted(int64(maxWorkers))
-		// Output array for results
-		out = make([]int, 64)
+		// Pool holds a weighted semaphore with capacity = maxWorkers
+		workers = pool.New(ctx, maxWorkers)
+		// Output arrays for per-input results
+		results = make(collatz.Results, *count)
+		records = make([]record, *count)
+		// Completed records in streaming mode
+		completed = make(chan record, maxWorkers)
+		written   = make(chan error)
 	)
 
-	fmt.Printf("Computing with up to %d concurrent workers\n", maxWorkers)
+	fmt.Fprintf(banner, "Computing with up to %d concurrent workers\n", maxWorkers)
+
+	// ==================== STREAMING WRITER ====================
+	// A single goroutine owns the writer, so records never interleave
+	if *stream {
+		go func() {
+			var err error
+			for rec := range completed {
+				if err == nil {
+					err = out.Write(rec)
+				}
+			}
+			written <- err
+		}()
+	}
+	// ==========================================================
 
 	// Compute the output using up to maxWorkers goroutines at a time
-	for i := range out {
-		// ==================== ACQUIRE SEMAPHORE ====================
-		// When maxWorkers goroutines are in flight, Acquire blocks
+	for i := range results {
+		// ==================== SUBMIT TASK ====================
+		// When maxWorkers tasks are in flight, Submit blocks
 		// until one of the workers finishes
-		if err := sem.Acquire(ctx, 1); err != nil {
-			log.Printf("Failed to acquire semaphore: %v", err)
+		err := workers.Submit(ctx, func(ctx context.Context) error {
+			worker, _ := pool.WorkerID(ctx)
+			begin := time.Now()
+
+			// Compute Collatz steps for this number; errors and panics
+			// are stored in the result rather than failing the pool
+			results[i] = compute(*start + i)
+
+			records[i] = newRecord(results[i], worker, time.Since(begin))
+			if *stream {
+				completed <- records[i]
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Failed to submit task: %v", err)
 			break
 		}
-		// ===========================================================
-
-		// Launch worker goroutine
-		go func(i int) {
-			// Ensure semaphore is released when goroutine completes
-			defer sem.Release(1)
-
-			// Compute Collatz steps for this number
-			out[i] = collatzSteps(i + 1)
-		}(i)
+		// =====================================================
 	}
 
 	// ==================== WAIT FOR COMPLETION ====================
-	// Acquire all tokens to wait for any remaining workers to finish
-	// Alternative: Use errgroup.Group for more sophisticated coordination
-	if err := sem.Acquire(ctx, int64(maxWorkers)); err != nil {
-		log.Printf("Failed to acquire semaphore: %v", err)
+	// Wait for any remaining workers to finish
+	if err := workers.Wait(); err != nil {
+		log.Printf("Worker failed: %v", err)
 	}
 	// =============================================================
 
-	fmt.Println("\nCollatz steps for numbers 1-64:")
-	fmt.Println(out)
-}
-
-// collatzSteps computes the number of steps to reach 1 under the Collatz conjecture
-// Collatz conjecture: For any positive integer n:
-// - If n is even: n → n/2
-// - If n is odd: n → 3n+1
-// Repeat until reaching 1
-//
-// Parameters:
-//   - n: Starting number (must be positive)
-//
-// Returns:
-//   - Number of steps to reach 1
-//
-// Reference: https://en.wikipedia.org/wiki/Collatz_conjecture
-func collatzSteps(n int) (steps int) {
-	if n <= 0 {
-		panic("nonpositive input")
-	}
-
-	for ; n > 1; steps++ {
-		// Check for overflow (too many steps)
-		if steps < 0 {
-			panic("too many steps")
+	// ==================== STRUCTURED OUTPUT ====================
+	if structured {
+		var err error
+		if *stream {
+			close(completed)
+			err = <-written
+		} else {
+			// Batch mode: every record, in input order
+			for _, rec := range records {
+				if err = out.Write(rec); err != nil {
+					break
+				}
+			}
 		}
-
-		if n%2 == 0 {
-			// Even: divide by 2
-			n /= 2
-			continue
+		if err == nil {
+			err = out.Close()
 		}
-
-		// Odd: multiply by 3 and add 1
-		// Check for integer overflow before computing
-		const maxInt = int(^uint(0) >> 1)
-		if n > (maxInt-1)/3 {
-			panic("overflow")
+		if err != nil {
+			log.Fatal(err)
 		}
-		n = 3*n + 1
+		return
 	}
+	// ===========================================================
 
-	return steps
+	// Print the original summary, failing like the structured formats do
+	// if stdout cannot be written
+	if err := writeSummary(os.Stdout, *start, results); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -1,52 +1,87 @@
-// Go Concurrency Essentials - Semaphore Pattern Using Buffered Channel
-// Description: Demonstrates using buffered channels as counting semaphores
-//              to limit concurrent goroutine execution
+// Go Concurrency Essentials - Semaphore Pattern Using a Worker Pool
+// Description: Demonstrates limiting concurrent goroutine execution with
+//              pool.Pool, which hands out a fixed number of slots
+//
+// A rate limiter (token or leaky bucket) can also bound how fast tasks
+// start; each task prints its start time so both limits show up
 
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
-	"sync"
+	"log"
 	"time"
+
+	"essentials/limiter"
+	"essentials/pool"
+	"essentials/rate"
 )
 
-// main demonstrates resource pool pattern using buffered channel as semaphore
+// main demonstrates resource pool pattern using a bounded worker pool
 func main() {
+	bucket := flag.String("bucket", "token", "start-rate limiter: token, leaky or none")
+	perSecond := flag.Float64("rate", 2, "task starts per second")
+	burst := flag.Int("burst", 3, "token bucket size")
+	flag.Parse()
+	if !(*perSecond > 0) { // Also rejects NaN
+		log.Fatalf("-rate must be positive, got %v", *perSecond)
+	}
+
 	maxGoroutines := 5 // Maximum concurrent goroutines allowed
+	ctx := context.Background()
 
-	// Buffered channel acts as a counting semaphore
+	// Pool acts as a counting semaphore
 	// Capacity = max concurrent goroutines
-	semaphore := make(chan struct{}, maxGoroutines)
-
-	var wg sync.WaitGroup
+	var workers *pool.Pool
+	switch *bucket {
+	case "none":
+		workers = pool.New(ctx, maxGoroutines)
+	case "token", "leaky":
+		// ==================== RATE LIMIT ====================
+		// Slots bound how many run; the bucket bounds how fast they start
+		var lim rate.Limiter = rate.NewTokenBucket(*perSecond, *burst, nil)
+		if *bucket == "leaky" {
+			lim = rate.NewLeakyBucket(*perSecond, 20, nil) // Room for all 20 tasks
+		}
+		slots := limiter.New(maxGoroutines)
+		workers = pool.NewWithLimiter(ctx, rate.Throttle(slots, lim))
+		fmt.Printf("%d slots, %s bucket at %.1f starts/s\n\n", maxGoroutines, *bucket, *perSecond)
+		// ====================================================
+	default:
+		log.Fatalf("unknown -bucket %q (want token, leaky or none)", *bucket)
+	}
+	start := time.Now()
 
 	// Launch 20 tasks, but only 5 can run concurrently
 	for i := range 20 {
-		wg.Add(1)
-
-		go func(i int) {
-			defer wg.Done()
-
-			// ==================== ACQUIRE SEMAPHORE ====================
-			// Send to channel (blocks if channel is full)
-			semaphore <- struct{}{}
-			// ===========================================================
-
-			// Ensure semaphore is released when goroutine exits
-			defer func() {
-				<-semaphore // Receive from channel (frees one slot)
-			}()
-
+		// ==================== ACQUIRE SLOT ====================
+		// Submit blocks while all 5 slots are in use; the slot is
+		// released automatically when the task returns
+		err := workers.Submit(ctx, func(ctx context.Context) error {
 			// ==================== CRITICAL WORK ====================
 			// Simulate a task that takes 2 seconds
-			fmt.Printf("Running task %d\n", i)
-			time.Sleep(2 * time.Second)
+			fmt.Printf("Running task %d (started at %.1fs)\n", i, time.Since(start).Seconds())
+			select {
+			case <-time.After(2 * time.Second):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 			// =======================================================
-		}(i)
+		})
+		// ======================================================
+		if err != nil {
+			log.Printf("Failed to submit task %d: %v", i, err)
+			break
+		}
 	}
 
 	// Wait for all tasks to complete
-	wg.Wait()
+	if err := workers.Wait(); err != nil {
+		log.Printf("Task failed: %v", err)
+	}
 
 	fmt.Println("\nAll tasks completed!")
 }
@@ -0,0 +1,133 @@
+// Go Concurrency Essentials - Semaphore Contention Metrics
+// Description: Runs the same bursty workload through instrumented semaphores
+//              of several sizes and reports wait times, holders, timeouts
+//              and cancellations, to show how the metrics guide pool sizing
+//
+// Every client has a deadline for getting a slot and some clients give up
+// early (cancellation), so small pools show up as timeouts and long waits
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+
+	"essentials/instrument"
+)
+
+// workload describes the burst of clients sent at each semaphore
+type workload struct {
+	clients  int           // Clients arriving at once
+	work     time.Duration // Time each client holds its slot
+	deadline time.Duration // How long a client waits for a slot
+	cancelN  int           // Every cancelN-th client gives up after cancelAt
+	cancelAt time.Duration
+}
+
+// run sends the burst at sem and waits for every client to finish
+func run(sem *instrument.Instrumented, w workload) time.Duration {
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range w.clients {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), w.deadline)
+			defer cancel()
+			if w.cancelN > 0 && i%w.cancelN == w.cancelN-1 {
+				// This client loses interest before its deadline
+				time.AfterFunc(w.cancelAt, cancel)
+			}
+
+			// ==================== ACQUIRE ====================
+			if err := sem.Acquire(ctx); err != nil {
+				return // Counted as a timeout or cancellation
+			}
+			// ==================== CRITICAL WORK ====================
+			time.Sleep(w.work)
+			// ==================== RELEASE ====================
+			sem.Release()
+		})
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// newSemaphore builds the underlying semaphore being measured
+func newSemaphore(impl string, size int) instrument.Semaphore {
+	switch impl {
+	case "chan":
+		return instrument.Channel(size)
+	case "weighted":
+		return instrument.Weighted(semaphore.NewWeighted(int64(size)))
+	}
+	log.Fatalf("unknown -impl %q (want chan or weighted)", impl)
+	return nil
+}
+
+// main sweeps semaphore sizes and prints the metrics
+func main() {
+	impl := flag.String("impl", "chan", "semaphore to instrument: chan or weighted")
+	sizes := flag.String("sizes", "2,5,10", "comma-separated semaphore sizes")
+	format := flag.String("format", "table", "output format: table or prom")
+	clients := flag.Int("clients", 40, "clients per burst")
+	flag.Parse()
+
+	w := workload{
+		clients:  *clients,
+		work:     10 * time.Millisecond,
+		deadline: 60 * time.Millisecond,
+		cancelN:  10,
+		cancelAt: 5 * time.Millisecond,
+	}
+
+	var sems []*instrument.Instrumented
+	var elapsed []time.Duration
+	for field := range strings.SplitSeq(*sizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size < 1 {
+			log.Fatalf("bad size %q", field)
+		}
+		sem := instrument.Wrap(fmt.Sprintf("%s-%d", *impl, size), newSemaphore(*impl, size))
+		elapsed = append(elapsed, run(sem, w))
+		sems = append(sems, sem)
+	}
+
+	// ==================== OUTPUT ====================
+	// Built in full, then written once, so a failed write is reported
+	var out strings.Builder
+	switch *format {
+	case "prom":
+		instrument.WritePrometheus(&out, sems...)
+	case "table":
+		fmt.Fprintf(&out, "%d clients, %v work each, %v deadline, every %dth cancels after %v\n\n",
+			w.clients, w.work, w.deadline, w.cancelN, w.cancelAt)
+		for _, sem := range sems {
+			sem.WriteTable(&out)
+			fmt.Fprintln(&out)
+		}
+
+		fmt.Fprintf(&out, "%-12s %8s %8s %9s %6s %10s %10s %9s\n",
+			"semaphore", "acquired", "timeouts", "cancelled", "peak", "mean wait", "max wait", "elapsed")
+		for i, sem := range sems {
+			st := sem.Stats()
+			fmt.Fprintf(&out, "%-12s %8d %8d %9d %6d %10v %10v %9v\n",
+				st.Name, st.Acquisitions, st.Timeouts, st.Cancellations, st.PeakHolders,
+				st.MeanWait().Round(time.Microsecond), st.WaitMax.Round(time.Microsecond),
+				elapsed[i].Round(time.Millisecond))
+		}
+	default:
+		log.Fatalf("unknown -format %q (want table or prom)", *format)
+	}
+	if _, err := os.Stdout.WriteString(out.String()); err != nil {
+		log.Fatal(err)
+	}
+	// ================================================
+}
@@ -0,0 +1,178 @@
+// Go Concurrency Essentials - Sequence Lock
+// Description: SeqLock[T] holds a value that is read far more often than
+//              it is written. Writers make the sequence number odd, update
+//              the value, and make it even again. Readers never block or
+//              write shared memory: they copy the value and retry if the
+//              sequence number was odd or changed meanwhile (a torn read)
+//
+// A plain copy that overlaps a write is a data race in Go, so the value is
+// stored as 64-bit words and copied with atomic loads and stores. That
+// limits T to types without pointers (numbers, bools, arrays and structs
+// of them), which NewSeqLock checks. The atomics make each word
+// consistent; the sequence number makes the whole value consistent
+
+package seqlock
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// SeqLock is a sequence lock around a pointer-free value of type T
+type SeqLock[T any] struct {
+	seq   atomic.Uint64   // Odd while a write is in progress
+	mutex sync.Mutex      // Serialises writers
+	words []atomic.Uint64 // The value, in native byte order
+	size  int             // unsafe.Sizeof(T)
+}
+
+// NewSeqLock creates a SeqLock holding v; it panics if T contains pointers
+func NewSeqLock[T any](v T) *SeqLock[T] {
+	typ := reflect.TypeFor[T]()
+	if hasPointers(typ) {
+		panic(fmt.Sprintf("seqlock: %v contains pointers", typ))
+	}
+	size := int(unsafe.Sizeof(v))
+	s := &SeqLock[T]{words: make([]atomic.Uint64, (size+7)/8), size: size}
+	s.store(&v)
+	return s
+}
+
+// hasPointers reports whether values of t contain pointers
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	}
+	return true // Pointers, strings, slices, maps, channels, funcs, interfaces
+}
+
+// bytesOf views v's memory as a byte slice
+func bytesOf[T any](v *T, size int) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(v)), size)
+}
+
+// store copies *v into the words (caller holds mutex, or is the constructor)
+func (s *SeqLock[T]) store(v *T) {
+	full := s.fullWords()
+	if full > 0 {
+		src := unsafe.Slice((*uint64)(unsafe.Pointer(v)), full)
+		for i, w := range src {
+			s.words[i].Store(w)
+		}
+	}
+	if full < len(s.words) { // Trailing bytes, or a T aligned below 8
+		src := bytesOf(v, s.size)
+		for i := full; i < len(s.words); i++ {
+			var w [8]byte
+			copy(w[:], src[i*8:])
+			s.words[i].Store(binary.NativeEndian.Uint64(w[:]))
+		}
+	}
+}
+
+// load copies the words into *v, which may end up torn
+func (s *SeqLock[T]) load(v *T) {
+	full := s.fullWords()
+	if full > 0 {
+		dst := unsafe.Slice((*uint64)(unsafe.Pointer(v)), full)
+		for i := range dst {
+			dst[i] = s.words[i].Load()
+		}
+	}
+	if full < len(s.words) {
+		dst := bytesOf(v, s.size)
+		for i := full; i < len(s.words); i++ {
+			var w [8]byte
+			binary.NativeEndian.PutUint64(w[:], s.words[i].Load())
+			copy(dst[i*8:], w[:])
+		}
+	}
+}
+
+// fullWords is how many leading words of a T can be copied as uint64s:
+// all whole words if T is 8-byte aligned, otherwise none
+func (s *SeqLock[T]) fullWords() int {
+	var v T
+	if unsafe.Alignof(v) < 8 {
+		return 0
+	}
+	return s.size / 8
+}
+
+// ==================== READERS ====================
+
+// TryRead makes one read attempt
+// Returns:
+//   - The value, and true if no write overlapped the copy. On false the
+//     value may be torn and must not be used
+func (s *SeqLock[T]) TryRead() (T, bool) {
+	var v T
+	ok := s.tryReadInto(&v)
+	return v, ok
+}
+
+// tryReadInto makes one read attempt into *v
+func (s *SeqLock[T]) tryReadInto(v *T) bool {
+	before := s.seq.Load()
+	s.load(v)
+	return before%2 == 0 && s.seq.Load() == before
+}
+
+// Read returns a consistent copy of the value, retrying while writes
+// overlap
+func (s *SeqLock[T]) Read() T {
+	var v T
+	for n := 1; ; n++ {
+		if s.tryReadInto(&v) {
+			return v
+		}
+		if n%16 == 0 {
+			runtime.Gosched() // Let a descheduled writer finish
+		}
+	}
+}
+
+// ==================== WRITERS ====================
+
+// Write replaces the value
+func (s *SeqLock[T]) Write(v T) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.seq.Add(1) // Odd: readers will retry
+	s.store(&v)
+	s.seq.Add(1) // Even: value is consistent again
+}
+
+// Update replaces the value with fn applied to it; writers are serialised,
+// so no update is lost
+func (s *SeqLock[T]) Update(fn func(T) T) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var v T
+	s.load(&v) // No other writer, so this copy is consistent
+	v = fn(v)
+	s.seq.Add(1)
+	s.store(&v)
+	s.seq.Add(1)
+}
+
+// Seq returns the sequence number: twice the number of completed writes,
+// plus one while a write is in progress
+func (s *SeqLock[T]) Seq() uint64 { return s.seq.Load() }
@@ -0,0 +1,236 @@
+// Go Concurrency Essentials - Sequence Lock Tests
+// Description: Hammers a SeqLock with a writer that fills a block with one
+//              number per write and readers that check every value they get
+//              back is uniform. Failed attempts (TryRead returning false) are
+//              counted, along with how many of them really were torn, to
+//              show the sequence number is what stops torn values escaping.
+//              The Update test mirrors the 10 x 1000 increments of mutex.go
+//
+// The benchmark compares reading a read-mostly config struct through
+// SeqLock, sync.RWMutex and atomic.Pointer (copy on write) while a writer
+// updates it now and then
+
+package seqlock
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// ==================== STRESS ====================
+// block is written with every element equal, so any mix of two writes shows
+type block [32]uint64
+
+// uniform reports whether every element of b is the same
+func uniform(b block) bool {
+	for _, x := range b {
+		if x != b[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// stressCounts are the outcomes of the stress run
+type stressCounts struct {
+	reads, retries, tornCaught, tornReturned, writes int64
+}
+
+// stress runs one writer and readers readers for d
+func stress(readers int, d time.Duration) stressCounts {
+	s := NewSeqLock(block{})
+	var c stressCounts
+	var reads, retries, caught, returned atomic.Int64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		for n := uint64(1); ; n++ {
+			select {
+			case <-stop:
+				c.writes = int64(n - 1)
+				return
+			default:
+			}
+			var b block
+			for i := range b {
+				b[i] = n
+			}
+			s.Write(b)
+		}
+	})
+	for range readers {
+		wg.Go(func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				v, ok := s.TryRead()
+				switch {
+				case ok && !uniform(v):
+					returned.Add(1)
+				case ok:
+					reads.Add(1)
+				case !uniform(v):
+					retries.Add(1)
+					caught.Add(1)
+				default:
+					retries.Add(1)
+				}
+			}
+		})
+	}
+	time.Sleep(d)
+	close(stop)
+	wg.Wait()
+	c.reads, c.retries, c.tornCaught, c.tornReturned = reads.Load(), retries.Load(), caught.Load(), returned.Load()
+	return c
+}
+
+// updates runs the adds pattern through Update
+func updates(goroutines, increments int) int {
+	s := NewSeqLock(0)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range increments {
+				s.Update(func(n int) int { return n + 1 })
+			}
+		})
+	}
+	wg.Wait()
+	return s.Read()
+}
+
+func TestNoTornReads(t *testing.T) {
+	c := stress(4, 200*time.Millisecond)
+	t.Logf("writes %d, good reads %d, retries %d (of which really torn: %d), torn returned %d",
+		c.writes, c.reads, c.retries, c.tornCaught, c.tornReturned)
+	if c.tornReturned != 0 {
+		t.Errorf("TryRead returned %d torn values", c.tornReturned)
+	}
+	if c.reads == 0 {
+		t.Error("no read succeeded")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	if total := updates(10, 1000); total != 10000 {
+		t.Errorf("10 goroutines x 1000 increments = %d, want 10000", total)
+	}
+}
+
+// ================================================
+
+// ==================== BENCHMARK ====================
+// Config is a read-mostly settings struct
+type Config struct {
+	Version    uint64
+	MaxConns   int64
+	Timeout    time.Duration
+	RatePerSec float64
+	Weights    [4]float64
+	Debug      bool
+}
+
+// store is one way of sharing a Config
+type store interface {
+	Load() Config
+	Store(Config)
+}
+
+type seqStore struct{ s *SeqLock[Config] }
+
+func (s seqStore) Load() Config   { return s.s.Read() }
+func (s seqStore) Store(c Config) { s.s.Write(c) }
+
+type rwStore struct {
+	mutex sync.RWMutex
+	c     Config
+}
+
+func (s *rwStore) Load() Config {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.c
+}
+
+func (s *rwStore) Store(c Config) {
+	s.mutex.Lock()
+	s.c = c
+	s.mutex.Unlock()
+}
+
+type ptrStore struct{ p atomic.Pointer[Config] }
+
+func (s *ptrStore) Load() Config   { return *s.p.Load() }
+func (s *ptrStore) Store(c Config) { s.p.Store(&c) } // Fresh copy per write
+
+// stores lists the implementations, in report order
+var stores = []struct {
+	name string
+	new  func() store
+}{
+	{"seqlock", func() store { return seqStore{NewSeqLock(Config{})} }},
+	{"rwmutex", func() store { return &rwStore{} }},
+	{"atomic.Pointer", func() store {
+		s := &ptrStore{}
+		s.p.Store(&Config{})
+		return s
+	}},
+}
+
+// BenchmarkReadMostly measures one config Load per op, with the b.N loads
+// split across a growing number of readers and one writer storing a new
+// config every 100µs
+func BenchmarkReadMostly(b *testing.B) {
+	const writeEvery = 100 * time.Microsecond
+	for _, st := range stores {
+		for _, g := range []int{1, 4, 16} {
+			b.Run(fmt.Sprintf("%s/g=%d", st.name, g), func(b *testing.B) {
+				s := st.new()
+				stop := make(chan struct{})
+				writerDone := make(chan struct{})
+				go func() {
+					defer close(writerDone)
+					for v := uint64(1); ; v++ {
+						select {
+						case <-stop:
+							return
+						case <-time.After(writeEvery):
+							s.Store(Config{Version: v, MaxConns: int64(v)})
+						}
+					}
+				}()
+
+				var wg sync.WaitGroup
+				var sink atomic.Uint64
+				b.ResetTimer()
+				for i := range g {
+					share := b.N / g
+					if i < b.N%g {
+						share++
+					}
+					wg.Go(func() {
+						var sum uint64
+						for range share {
+							sum += s.Load().Version
+						}
+						sink.Add(sum)
+					})
+				}
+				wg.Wait()
+				b.StopTimer()
+				close(stop)
+				<-writerDone
+			})
+		}
+	}
+}
+
+// ===================================================
@@ -0,0 +1,196 @@
+// Go Concurrency Essentials - Spinlocks
+// Description: Classic busy-waiting locks built from sync/atomic, each a
+//              sync.Locker so it can replace sync.Mutex anywhere
+//              - TAS: test-and-set on one flag
+//              - TTAS: test-and-test-and-set with exponential backoff
+//              - Ticket: FIFO "take a number" lock
+//              - MCS: FIFO queue lock, each waiter spins on its own node
+//              - CLH: FIFO queue lock, each waiter spins on its predecessor
+//
+// Spinning only helps when the holder is running on another core. Go may
+// run many goroutines on few threads, so every spin loop yields with
+// runtime.Gosched after a few attempts; otherwise a spinner could burn its
+// whole time slice while the holder waits for a CPU
+
+package spinlock
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"essentials/padded"
+)
+
+// spinsBeforeYield is how many busy checks a waiter makes before yielding
+const spinsBeforeYield = 16
+
+// Spin is called on every failed check of a busy-wait loop, with n counting
+// the checks so far; it yields every spinsBeforeYield calls. swlock's
+// software locks wait the same way
+func Spin(n *int) {
+	*n++
+	if *n%spinsBeforeYield == 0 {
+		runtime.Gosched()
+	}
+}
+
+// ==================== TAS ====================
+// TAS is a test-and-set lock: every attempt is an atomic write, so waiters
+// keep stealing the cache line from each other and from the holder
+type TAS struct {
+	held atomic.Bool
+}
+
+func (l *TAS) Lock() {
+	n := 0
+	for !l.held.CompareAndSwap(false, true) {
+		Spin(&n)
+	}
+}
+
+func (l *TAS) Unlock() { l.held.Store(false) }
+
+// ==================== TTAS ====================
+// TTAS spins on a plain load (served from the local cache) and only tries
+// the atomic write when the lock looks free; after a lost race it backs off
+// for an exponentially growing time so waiters stop colliding
+type TTAS struct {
+	held atomic.Bool
+}
+
+const (
+	minBackoff = 1
+	maxBackoff = 1024
+)
+
+func (l *TTAS) Lock() {
+	backoff := minBackoff
+	n := 0
+	for {
+		for l.held.Load() {
+			Spin(&n)
+		}
+		if l.held.CompareAndSwap(false, true) {
+			return
+		}
+		// Lost the race: back off for a doubling number of spins
+		for range backoff {
+			Spin(&n)
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (l *TTAS) Unlock() { l.held.Store(false) }
+
+// ==================== TICKET ====================
+// Ticket hands out increasing tickets and serves them in order, so the
+// lock is FIFO fair; all waiters still spin on the one serving counter
+type Ticket struct {
+	next    padded.Uint64 // Next ticket to hand out
+	serving padded.Uint64 // Ticket allowed in
+}
+
+func (l *Ticket) Lock() {
+	my := l.next.Add(1) - 1
+	n := 0
+	for l.serving.Load() != my {
+		Spin(&n)
+	}
+}
+
+func (l *Ticket) Unlock() { l.serving.Add(1) }
+
+// ==================== MCS ====================
+// mcsNode is one waiter in an MCS queue
+type mcsNode struct {
+	next   atomic.Pointer[mcsNode]
+	locked atomic.Bool
+	_      [padded.CacheLineSize - 16]byte // Waiters spin on their own line
+}
+
+// MCS queues waiters in a linked list; each spins on its own node until its
+// predecessor hands the lock over, so only one cache line changes hands
+// per release no matter how many are waiting
+type MCS struct {
+	tail  atomic.Pointer[mcsNode]
+	owner *mcsNode // Holder's node (only touched by the holder)
+}
+
+func (l *MCS) Lock() {
+	me := &mcsNode{}
+	me.locked.Store(true)
+	pred := l.tail.Swap(me)
+	if pred != nil {
+		pred.next.Store(me)
+		n := 0
+		for me.locked.Load() {
+			Spin(&n)
+		}
+	}
+	l.owner = me
+}
+
+func (l *MCS) Unlock() {
+	me := l.owner
+	next := me.next.Load()
+	if next == nil {
+		// No known successor: try to mark the queue empty
+		if l.tail.CompareAndSwap(me, nil) {
+			return
+		}
+		// A successor swapped itself in but has not linked yet
+		n := 0
+		for next = me.next.Load(); next == nil; next = me.next.Load() {
+			Spin(&n)
+		}
+	}
+	next.locked.Store(false)
+}
+
+// ==================== CLH ====================
+// clhNode is one waiter's flag in a CLH queue
+type clhNode struct {
+	locked atomic.Bool
+	_      [padded.CacheLineSize - 4]byte
+}
+
+// CLH keeps an implicit queue: each waiter swaps its node into the tail and
+// spins on its predecessor's flag, which the predecessor clears on unlock
+type CLH struct {
+	tail  atomic.Pointer[clhNode]
+	owner *clhNode // Holder's node (only touched by the holder)
+	once  sync.Once
+}
+
+func (l *CLH) Lock() {
+	// The queue starts with a released dummy node so the zero CLH is usable
+	l.once.Do(func() { l.tail.Store(&clhNode{}) })
+	me := &clhNode{}
+	me.locked.Store(true)
+	pred := l.tail.Swap(me)
+	n := 0
+	for pred.locked.Load() {
+		Spin(&n)
+	}
+	l.owner = me
+}
+
+func (l *CLH) Unlock() { l.owner.locked.Store(false) }
+
+// ==================== REGISTRY ====================
+// Named is a lock constructor with its display name
+type Named struct {
+	Name string
+	New  func() sync.Locker
+}
+
+// All lists every spinlock, in report order
+var All = []Named{
+	{"tas", func() sync.Locker { return &TAS{} }},
+	{"ttas", func() sync.Locker { return &TTAS{} }},
+	{"ticket", func() sync.Locker { return &Ticket{} }},
+	{"mcs", func() sync.Locker { return &MCS{} }},
+	{"clh", func() sync.Locker { return &CLH{} }},
+}
@@ -0,0 +1,89 @@
+// Go Concurrency Essentials - Spinlock Tests
+// Description: Mutual-exclusion stress test for every spinlock and a
+//              contention benchmark against sync.Mutex
+//
+// The stress test runs the adds loop from mutex.go on a plain int and also
+// counts how many goroutines are inside the critical section at once
+
+package spinlock
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// locks is every lock under test, sync.Mutex first as the baseline
+var locks = append([]Named{
+	{Name: "sync.Mutex", New: func() sync.Locker { return &sync.Mutex{} }},
+}, All...)
+
+// stress hammers lock with goroutines x increments critical sections
+// Returns:
+//   - Final total and the most goroutines seen inside at once
+func stress(lock sync.Locker, goroutines, increments int) (total, maxInside int64) {
+	var inside, peak atomic.Int64
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Go(func() {
+			for range increments {
+				lock.Lock()
+				// ==================== CRITICAL SECTION ====================
+				if n := inside.Add(1); n > peak.Load() {
+					peak.Store(n)
+				}
+				total++ // Plain, unsynchronised increment: only the lock protects it
+				inside.Add(-1)
+				// ==========================================================
+				lock.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return total, peak.Load()
+}
+
+func TestMutualExclusion(t *testing.T) {
+	const goroutines, increments = 32, 2000
+	for _, l := range locks {
+		t.Run(l.Name, func(t *testing.T) {
+			total, inside := stress(l.New(), goroutines, increments)
+			if want := int64(goroutines * increments); total != want {
+				t.Errorf("total %d, want %d", total, want)
+			}
+			if inside != 1 {
+				t.Errorf("max inside %d, want 1", inside)
+			}
+		})
+	}
+}
+
+// BenchmarkContention measures one Lock/Unlock pair per op, with the b.N
+// pairs split across a growing number of goroutines
+func BenchmarkContention(b *testing.B) {
+	for _, l := range locks {
+		for _, g := range []int{1, 2, 4, 8, 16} {
+			b.Run(fmt.Sprintf("%s/g=%d", l.Name, g), func(b *testing.B) {
+				b.ReportAllocs()
+				lock := l.New()
+				var counter int
+				var wg sync.WaitGroup
+				for i := range g {
+					share := b.N / g
+					if i < b.N%g {
+						share++
+					}
+					wg.Go(func() {
+						for range share {
+							lock.Lock()
+							counter++
+							lock.Unlock()
+						}
+					})
+				}
+				wg.Wait()
+			})
+		}
+	}
+}
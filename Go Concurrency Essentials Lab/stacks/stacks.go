@@ -0,0 +1,53 @@
+// Go Concurrency Essentials - Goroutine Stacks
+// Description: Goroutine ids and captured call stacks for the debugging
+//              tools (lockdep and racedetect), whose reports say which
+//              goroutine did something and where
+
+package stacks
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxFrames is how many stack frames Callers keeps
+const maxFrames = 16
+
+// GoID returns the current goroutine's id, parsed from its stack header
+// ("goroutine 42 [running]:"). Go hides ids on purpose; a debugging tool
+// that keys per-goroutine state is one of the few fair uses
+func GoID() int64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	id, _ := strconv.ParseInt(string(header[:bytes.IndexByte(header, ' ')]), 10, 64)
+	return id
+}
+
+// Callers captures the stack above the function that called it, so a Lock
+// or Load method records where it was called from
+// Returns:
+//   - Up to maxFrames program counters, innermost first
+func Callers() []uintptr {
+	pcs := make([]uintptr, maxFrames)
+	return pcs[:runtime.Callers(3, pcs)] // Skip runtime.Callers, Callers and the calling method
+}
+
+// Frames formats pcs as "function (file:line)" lines
+func Frames(pcs []uintptr) []string {
+	var out []string
+	iter := runtime.CallersFrames(pcs)
+	for {
+		f, more := iter.Next()
+		if f.Function != "" && f.Function != "runtime.goexit" {
+			file := f.File[strings.LastIndexByte(f.File, '/')+1:]
+			out = append(out, fmt.Sprintf("%s (%s:%d)", f.Function, file, f.Line))
+		}
+		if !more {
+			return out
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Go Concurrency Essentials - Ordered Streaming Fan-Out/Fan-In
+// Description: Generic ParallelMap over channels for inputs of unknown size
+//              Results are emitted as they complete, or in input order
+//              through a reorder buffer whose memory is bounded
+//
+// Cancellation: when ctx is cancelled, ParallelMap stops reading its input,
+// every goroutine it started exits, and the output channel is closed.
+// Producers built with FromSlice/Generate stop as well. A consumer that
+// stops reading early must cancel ctx so the pipeline can shut down
+
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// ==================== OPTIONS ====================
+// config holds ParallelMap settings
+type config struct {
+	ordered bool // Emit results in input order
+	window  int  // Max inputs in flight or buffered when ordered
+}
+
+// Option configures ParallelMap
+type Option func(*config)
+
+// Ordered makes ParallelMap emit results in input order
+// At most window inputs are in flight or waiting in the reorder buffer at
+// any time, so one slow item holds back at most window-1 finished results
+// (values below the worker count are raised to it)
+func Ordered(window int) Option {
+	return func(c *config) {
+		c.ordered = true
+		c.window = window
+	}
+}
+
+// =================================================
+
+// ParallelMap applies fn to every value from in using workers goroutines
+// Parameters:
+//   - ctx: Cancelling it stops the pipeline and closes the output
+//   - in: Input values; ParallelMap reads until in is closed
+//   - workers: Number of concurrent fn calls (values below 1 are treated as 1)
+//   - fn: Transformation to apply
+//   - opts: Ordered(window) to preserve input order
+//
+// Returns:
+//   - Output channel, closed after the last result (or on cancellation)
+func ParallelMap[In, Out any](ctx context.Context, in <-chan In, workers int, fn func(context.Context, In) Out, opts ...Option) <-chan Out {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	workers = max(workers, 1)
+
+	if cfg.ordered {
+		return orderedMap(ctx, in, workers, max(cfg.window, workers), fn)
+	}
+	return unorderedMap(ctx, in, workers, fn)
+}
+
+// unorderedMap emits results as soon as each worker finishes
+func unorderedMap[In, Out any](ctx context.Context, in <-chan In, workers int, fn func(context.Context, In) Out) <-chan Out {
+	out := make(chan Out)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Go(func() {
+			for {
+				v, ok := receive(ctx, in)
+				if !ok {
+					return
+				}
+				if !send(ctx, out, fn(ctx, v)) {
+					return
+				}
+			}
+		})
+	}
+
+	// Close the output once every worker has exited
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// item is an input or result tagged with its position in the input
+type item[T any] struct {
+	seq   int
+	value T
+}
+
+// orderedMap emits results in input order
+//
+// Pipeline:
+//
+//	dispatcher --jobs--> workers --done--> reorderer --out-->
+//
+// The dispatcher takes a window token before reading each input and the
+// reorderer returns it when that input's result is emitted, so no more
+// than window items are ever between the two
+func orderedMap[In, Out any](ctx context.Context, in <-chan In, workers, window int, fn func(context.Context, In) Out) <-chan Out {
+	out := make(chan Out)
+	jobs := make(chan item[In])
+	done := make(chan item[Out])
+	tokens := make(chan struct{}, window) // Counting semaphore for the window
+	var wg sync.WaitGroup
+
+	// ==================== DISPATCHER ====================
+	wg.Go(func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			if !send(ctx, tokens, struct{}{}) {
+				return
+			}
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			if !send(ctx, jobs, item[In]{seq: seq, value: v}) {
+				return
+			}
+		}
+	})
+
+	// ==================== WORKERS ====================
+	var workerWG sync.WaitGroup
+	for range workers {
+		workerWG.Go(func() {
+			for job := range jobs {
+				if !send(ctx, done, item[Out]{seq: job.seq, value: fn(ctx, job.value)}) {
+					return
+				}
+			}
+		})
+	}
+	wg.Go(func() {
+		workerWG.Wait()
+		close(done)
+	})
+
+	// ==================== REORDERER ====================
+	go func() {
+		defer func() {
+			wg.Wait() // Dispatcher and workers have exited
+			close(out)
+		}()
+		pending := make(map[int]Out, window) // Finished but not yet emitted
+		next := 0
+		for r := range done {
+			pending[r.seq] = r.value
+			for v, ok := pending[next]; ok; v, ok = pending[next] {
+				delete(pending, next)
+				if !send(ctx, out, v) {
+					drain(done)
+					return
+				}
+				<-tokens // Free a window slot for the dispatcher
+				next++
+			}
+		}
+	}()
+	return out
+}
+
+// ==================== CHANNEL HELPERS ====================
+
+// receive reads from ch unless ctx is cancelled first
+func receive[T any](ctx context.Context, ch <-chan T) (T, bool) {
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-ctx.Done():
+		var zero T
+		return zero, false
+	}
+}
+
+// send writes to ch unless ctx is cancelled first
+func send[T any](ctx context.Context, ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// drain discards values until ch is closed, unblocking its senders
+func drain[T any](ch <-chan T) {
+	for range ch {
+	}
+}
+
+// FromSlice streams values until they run out or ctx is cancelled
+func FromSlice[T any](ctx context.Context, values []T) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for _, v := range values {
+			if !send(ctx, ch, v) {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Generate streams next() results until next reports false or ctx is
+// cancelled. Use it for inputs whose size is not known in advance
+func Generate[T any](ctx context.Context, next func() (T, bool)) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for {
+			v, ok := next()
+			if !ok || !send(ctx, ch, v) {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Collect reads every value from ch into a slice
+func Collect[T any](ch <-chan T) []T {
+	var out []T
+	for v := range ch {
+		out = append(out, v)
+	}
+	return out
+}
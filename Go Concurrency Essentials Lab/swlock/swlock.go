@@ -0,0 +1,241 @@
+// Go Concurrency Essentials - Software Mutual Exclusion
+// Description: Locks built only from loads and stores of shared variables,
+//              with no atomic read-modify-write instruction:
+//              - Peterson and Dekker: two parties
+//              - Lamport's Bakery and the Filter lock: N parties
+//
+// All four assume sequential consistency: every goroutine sees loads and
+// stores in one global order. Plain Go variables give no such guarantee
+// (the compiler and CPU may reorder them), but sync/atomic loads and stores
+// are sequentially consistent, so every shared variable here is atomic.
+// Replacing them with plain fields breaks mutual exclusion
+//
+// The algorithms need to know which party is calling, so a Lock hands out
+// one sync.Locker per party: party i must only use Locker(i)
+
+package swlock
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"essentials/spinlock"
+)
+
+// Lock is a software lock for a fixed number of parties
+type Lock interface {
+	Locker(id int) *Party // Locker for party id (0 <= id < Parties)
+	Parties() int
+	Name() string
+}
+
+// Party is the Locker for one party of a lock; it satisfies sync.Locker
+type Party struct {
+	lock   func(id int, doorway func())
+	unlock func(id int)
+	id     int
+}
+
+func (p *Party) Lock()   { p.lock(p.id, nil) }
+func (p *Party) Unlock() { p.unlock(p.id) }
+
+// LockDoorway is Lock that calls doorway once the party has announced it
+// wants in (finished the algorithm's doorway) and before it starts waiting.
+// Fairness bounds count the acquisitions by others from that point: before
+// it, nobody knows the party is there
+func (p *Party) LockDoorway(doorway func()) { p.lock(p.id, doorway) }
+
+// passed runs the doorway hook, if any
+func passed(doorway func()) {
+	if doorway != nil {
+		doorway()
+	}
+}
+
+// checkID panics on an id outside [0, n)
+func checkID(id, n int) {
+	if id < 0 || id >= n {
+		panic(fmt.Sprintf("swlock: party %d out of range [0, %d)", id, n))
+	}
+}
+
+// ==================== PETERSON ====================
+// Peterson is Peterson's two-party lock: raise your flag, let the other go
+// first (become the victim), and wait while they want in and you are victim
+type Peterson struct {
+	flag   [2]atomic.Bool
+	victim atomic.Int32
+}
+
+// NewPeterson creates a two-party Peterson lock
+func NewPeterson() *Peterson { return &Peterson{} }
+
+func (l *Peterson) lock(i int, doorway func()) {
+	j := 1 - i
+	l.flag[i].Store(true)
+	l.victim.Store(int32(i))
+	passed(doorway)
+	n := 0
+	for l.flag[j].Load() && l.victim.Load() == int32(i) {
+		spinlock.Spin(&n)
+	}
+}
+
+func (l *Peterson) unlock(i int) { l.flag[i].Store(false) }
+
+func (l *Peterson) Locker(id int) *Party {
+	checkID(id, 2)
+	return &Party{lock: l.lock, unlock: l.unlock, id: id}
+}
+
+func (l *Peterson) Parties() int { return 2 }
+func (l *Peterson) Name() string { return "peterson" }
+
+// ==================== DEKKER ====================
+// Dekker is Dekker's two-party lock, the first correct software solution:
+// on conflict, whoever does not hold the turn withdraws until it gets it
+type Dekker struct {
+	wants [2]atomic.Bool
+	turn  atomic.Int32
+}
+
+// NewDekker creates a two-party Dekker lock
+func NewDekker() *Dekker { return &Dekker{} }
+
+func (l *Dekker) lock(i int, doorway func()) {
+	j := 1 - i
+	l.wants[i].Store(true)
+	passed(doorway)
+	n := 0
+	for l.wants[j].Load() {
+		if l.turn.Load() != int32(i) {
+			// Not our turn: step back until it is
+			l.wants[i].Store(false)
+			for l.turn.Load() != int32(i) {
+				spinlock.Spin(&n)
+			}
+			l.wants[i].Store(true)
+		}
+		spinlock.Spin(&n)
+	}
+}
+
+func (l *Dekker) unlock(i int) {
+	l.turn.Store(int32(1 - i)) // Hand the turn to the other party
+	l.wants[i].Store(false)
+}
+
+func (l *Dekker) Locker(id int) *Party {
+	checkID(id, 2)
+	return &Party{lock: l.lock, unlock: l.unlock, id: id}
+}
+
+func (l *Dekker) Parties() int { return 2 }
+func (l *Dekker) Name() string { return "dekker" }
+
+// ==================== BAKERY ====================
+// Bakery is Lamport's bakery lock: take a number one higher than any in
+// use, then wait for every smaller (number, id) pair to be served. Parties
+// are served first-come first-served once they hold a number
+type Bakery struct {
+	choosing []atomic.Bool  // Party is picking a number
+	number   []atomic.Int64 // Party's number (0 = not interested)
+}
+
+// NewBakery creates a bakery lock for n parties
+func NewBakery(n int) *Bakery {
+	return &Bakery{choosing: make([]atomic.Bool, n), number: make([]atomic.Int64, n)}
+}
+
+func (l *Bakery) lock(i int, doorway func()) {
+	// ==================== DOORWAY ====================
+	l.choosing[i].Store(true)
+	var highest int64
+	for k := range l.number {
+		highest = max(highest, l.number[k].Load())
+	}
+	mine := highest + 1
+	l.number[i].Store(mine)
+	l.choosing[i].Store(false)
+	// =================================================
+	passed(doorway)
+
+	n := 0
+	for k := range l.number {
+		if k == i {
+			continue
+		}
+		for l.choosing[k].Load() {
+			spinlock.Spin(&n) // Wait until k has finished picking
+		}
+		for {
+			theirs := l.number[k].Load()
+			if theirs == 0 || theirs > mine || (theirs == mine && k > i) {
+				break
+			}
+			spinlock.Spin(&n) // k is ahead of us
+		}
+	}
+}
+
+func (l *Bakery) unlock(i int) { l.number[i].Store(0) }
+
+func (l *Bakery) Locker(id int) *Party {
+	checkID(id, len(l.number))
+	return &Party{lock: l.lock, unlock: l.unlock, id: id}
+}
+
+func (l *Bakery) Parties() int { return len(l.number) }
+func (l *Bakery) Name() string { return "bakery" }
+
+// ==================== FILTER ====================
+// Filter generalises Peterson to n parties with n-1 waiting rooms (levels).
+// At each level at least one party is held back (the level's victim), so
+// at most n-L parties get past level L and only one reaches the lock
+type Filter struct {
+	level  []atomic.Int32 // Level each party is trying to pass (0 = none)
+	victim []atomic.Int32 // Victim of each level
+}
+
+// NewFilter creates a filter lock for n parties
+func NewFilter(n int) *Filter {
+	return &Filter{level: make([]atomic.Int32, n), victim: make([]atomic.Int32, n)}
+}
+
+func (l *Filter) lock(i int, doorway func()) {
+	if len(l.level) < 2 {
+		passed(doorway) // A lone party has no waiting room to enter
+	}
+	n := 0
+	for L := int32(1); L < int32(len(l.level)); L++ {
+		l.level[i].Store(L)
+		l.victim[L].Store(int32(i))
+		if L == 1 {
+			passed(doorway) // Entering the first waiting room announces us
+		}
+		// Wait while someone else is at this level or higher and we are victim
+		for l.victim[L].Load() == int32(i) && l.others(i, L) {
+			spinlock.Spin(&n)
+		}
+	}
+}
+
+// others reports whether a party other than i is at level L or above
+func (l *Filter) others(i int, L int32) bool {
+	for k := range l.level {
+		if k != i && l.level[k].Load() >= L {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Filter) unlock(i int) { l.level[i].Store(0) }
+
+func (l *Filter) Locker(id int) *Party {
+	checkID(id, len(l.level))
+	return &Party{lock: l.lock, unlock: l.unlock, id: id}
+}
+
+func (l *Filter) Parties() int { return len(l.level) }
+func (l *Filter) Name() string { return "filter" }
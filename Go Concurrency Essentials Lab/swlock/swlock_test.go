@@ -0,0 +1,106 @@
+// Go Concurrency Essentials - Software Mutual Exclusion Tests
+// Description: Runs the adds loop from mutex.go under every software lock,
+//              checking that the plain int counter reaches its target, that
+//              no two parties are ever inside together, and that Peterson
+//              and the Bakery keep their bypass bounds
+//
+// Each party yields inside and after the critical section, so the parties
+// interleave even on a single CPU
+
+package swlock
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// result is the outcome of one lock's run
+type result struct {
+	total     int   // Final counter value
+	maxInside int64 // Most parties seen in the critical section at once
+	maxBypass int64 // Most acquisitions by others during one wait
+}
+
+// run splits target increments over the lock's parties, each using its
+// own Locker in the adds loop
+func run(lock Lock, target int) result {
+	parties := lock.Parties()
+	var res result
+	var inside, peak, entries, bypass atomic.Int64
+	var wg sync.WaitGroup
+
+	for id := range parties {
+		share := target / parties
+		if id < target%parties {
+			share++
+		}
+		locker := lock.Locker(id)
+		wg.Go(func() {
+			for range share {
+				var before int64
+				locker.LockDoorway(func() { before = entries.Load() })
+				// ==================== CRITICAL SECTION ====================
+				waitedFor := entries.Add(1) - 1 - before
+				if waitedFor > bypass.Load() {
+					bypass.Store(waitedFor)
+				}
+				if n := inside.Add(1); n > peak.Load() {
+					peak.Store(n)
+				}
+				runtime.Gosched() // Invite other parties in while we hold the lock
+				res.total++       // Plain increment, protected only by the lock
+				inside.Add(-1)
+				// ==========================================================
+				locker.Unlock()
+				runtime.Gosched() // Let waiters compete for the lock
+			}
+		})
+	}
+	wg.Wait()
+	res.maxInside = peak.Load()
+	res.maxBypass = bypass.Load()
+	return res
+}
+
+func TestMutualExclusion(t *testing.T) {
+	const target, parties = 10000, 10
+	for _, tc := range []struct {
+		lock  Lock
+		bound int // Bypass bound, or -1 where the algorithm has none
+	}{
+		{NewPeterson(), 1},
+		{NewDekker(), -1},
+		{NewBakery(parties), parties - 1},
+		{NewFilter(parties), -1},
+	} {
+		t.Run(tc.lock.Name(), func(t *testing.T) {
+			res := run(tc.lock, target)
+			if res.total != target {
+				t.Errorf("total %d, want %d", res.total, target)
+			}
+			if res.maxInside != 1 {
+				t.Errorf("max inside %d, want 1", res.maxInside)
+			}
+			if tc.bound >= 0 && res.maxBypass > int64(tc.bound) {
+				t.Errorf("max bypass %d, bound %d", res.maxBypass, tc.bound)
+			}
+		})
+	}
+}
+
+func TestLockerOutOfRange(t *testing.T) {
+	for _, lock := range []Lock{NewPeterson(), NewDekker(), NewBakery(3), NewFilter(3)} {
+		for _, id := range []int{-1, lock.Parties()} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s: Locker(%d) did not panic", lock.Name(), id)
+					}
+				}()
+				lock.Locker(id)
+			}()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Go Concurrency Essentials - Software Mutual Exclusion Check
+// Description: Runs the adds loop from mutex.go under Peterson's, Dekker's,
+//              the Bakery and the Filter lock, checking that the plain int
+//              counter always reaches 10,000 and that no two parties are
+//              ever in the critical section together
+//
+// Each party yields inside and after the critical section, so the parties
+// interleave even on a single CPU. Fairness is observed by counting, for
+// every acquisition, how many other acquisitions happened between the
+// party finishing its doorway and getting in (its bypass count).
+// Exits 1 if any lock fails mutual exclusion or exceeds its bypass bound
+// (Peterson at most 1, Bakery at most parties-1; Dekker and Filter have none)
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"essentials/swlock"
+)
+
+// result is the outcome of one lock's run
+type result struct {
+	total     int   // Final counter value
+	maxInside int64 // Most parties seen in the critical section at once
+	maxBypass int64 // Most acquisitions by others during one wait
+	perParty  []int // Acquisitions per party
+}
+
+// run splits target increments over the lock's parties, each using its
+// own Locker in the adds loop
+func run(lock swlock.Lock, target int) result {
+	parties := lock.Parties()
+	res := result{perParty: make([]int, parties)}
+	var inside, peak, entries, bypass atomic.Int64
+	var wg sync.WaitGroup
+
+	for id := range parties {
+		share := target / parties
+		if id < target%parties {
+			share++
+		}
+		locker := lock.Locker(id)
+		wg.Go(func() {
+			for range share {
+				var before int64
+				locker.LockDoorway(func() { before = entries.Load() })
+				// ==================== CRITICAL SECTION ====================
+				waitedFor := entries.Add(1) - 1 - before
+				if waitedFor > bypass.Load() {
+					bypass.Store(waitedFor)
+				}
+				if n := inside.Add(1); n > peak.Load() {
+					peak.Store(n)
+				}
+				runtime.Gosched() // Invite other parties in while we hold the lock
+				res.total++       // Plain increment, protected only by the lock
+				res.perParty[id]++
+				inside.Add(-1)
+				// ==========================================================
+				locker.Unlock()
+				runtime.Gosched() // Let waiters compete for the lock
+			}
+		})
+	}
+	wg.Wait()
+	res.maxInside = peak.Load()
+	res.maxBypass = bypass.Load()
+	return res
+}
+
+// bypassBound is each algorithm's bound on max bypass for n parties, or -1
+// where it has none. Bypass is counted from the end of the doorway (see
+// swlock.Party.LockDoorway); before that nobody knows the party is waiting
+var bypassBound = map[string]func(n int) int{
+	// The victim rule lets the other party in at most once
+	"peterson": func(int) int { return 1 },
+	// Starvation-free, but a party that has stepped back for the turn can
+	// be passed again and again until it is scheduled
+	"dekker": func(int) int { return -1 },
+	// First-come first-served: only parties that chose a number while we
+	// were choosing ours can go first, each at most once
+	"bakery": func(n int) int { return n - 1 },
+	// Starvation-free, but parties can be overtaken arbitrarily often
+	"filter": func(int) int { return -1 },
+}
+
+// main runs every lock and checks mutual exclusion and the bypass bounds
+func main() {
+	target := flag.Int("total", 10000, "increments in total (split across parties)")
+	parties := flag.Int("parties", 10, "parties for the bakery and filter locks")
+	flag.Parse()
+	if *parties < 2 {
+		log.Fatal("-parties must be at least 2") // Mutual exclusion needs someone to exclude
+	}
+
+	locks := []swlock.Lock{
+		swlock.NewPeterson(),
+		swlock.NewDekker(),
+		swlock.NewBakery(*parties),
+		swlock.NewFilter(*parties),
+	}
+
+	failed := false
+	fmt.Printf("%-9s %7s %7s %10s %10s %6s  %s\n",
+		"lock", "parties", "total", "max inside", "max bypass", "bound", "status")
+	for _, lock := range locks {
+		res := run(lock, *target)
+		bound := bypassBound[lock.Name()](lock.Parties())
+		boundText := "none"
+		if bound >= 0 {
+			boundText = fmt.Sprint(bound)
+		}
+		status := "ok"
+		switch {
+		case res.total != *target || res.maxInside != 1:
+			status = "FAIL: mutual exclusion"
+			failed = true
+		case bound >= 0 && res.maxBypass > int64(bound):
+			status = "FAIL: bypass bound broken"
+			failed = true
+		}
+		fmt.Printf("%-9s %7d %7d %10d %10d %6s  %s\n",
+			lock.Name(), lock.Parties(), res.total, res.maxInside, res.maxBypass, boundText, status)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
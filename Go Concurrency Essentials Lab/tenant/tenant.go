@@ -0,0 +1,255 @@
+// Go Concurrency Essentials - Hierarchical Tenant Limits
+// Description: Concurrency limiter with a global limit and a per-tenant
+//              limit ("at most 5 tasks in total, at most 2 per tenant"),
+//              sharing global slots between tenants by weight
+//
+// Taking a tenant slot and then waiting for a global slot (or the reverse)
+// from two separate semaphores is the classic way to leak slots: a task
+// cancelled between the two steps must remember to give the first one
+// back, and tasks holding one while waiting for the other starve everybody
+// else. Here both levels are granted together under one mutex, so a task
+// holds either both slots or neither
+
+package tenant
+
+import (
+	"context"
+	"sync"
+)
+
+// Config sets one tenant's limit and share of the global slots
+type Config struct {
+	Limit  int // Most tasks this tenant may run at once
+	Weight int // Relative share of global slots when tenants compete
+}
+
+// tenant is the per-tenant state
+type tenant struct {
+	cfg      Config
+	custom   bool            // Set by SetTenant, so kept while idle
+	inFlight int             // Slots held by this tenant
+	granted  uint64          // Total acquisitions
+	waiters  []chan struct{} // Blocked Acquire calls, oldest first (closed when granted)
+}
+
+// ==================== LIMITER ====================
+// Limiter bounds concurrency globally and per tenant
+type Limiter struct {
+	mutex    sync.Mutex
+	global   int                // Global limit
+	inFlight int                // Slots held across all tenants
+	defaults Config             // Config for tenants not set explicitly
+	tenants  map[string]*tenant // Created on first use, removed when idle
+}
+
+// New creates a limiter
+// Parameters:
+//   - global: Most tasks running at once across all tenants
+//   - perTenant: Default per-tenant limit (each tenant gets weight 1)
+//
+// Returns:
+//   - Pointer to initialized limiter
+func New(global, perTenant int) *Limiter {
+	return &Limiter{
+		global:   max(global, 1),
+		defaults: Config{Limit: max(perTenant, 1), Weight: 1},
+		tenants:  make(map[string]*tenant),
+	}
+}
+
+// SetTenant overrides one tenant's limit and weight
+func (l *Limiter) SetTenant(name string, cfg Config) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	cfg.Limit = max(cfg.Limit, 1)
+	cfg.Weight = max(cfg.Weight, 1)
+	t := l.get(name)
+	t.cfg = cfg
+	t.custom = true
+	l.grant() // A raised limit may admit waiters
+}
+
+// get returns the tenant, creating it with the default config
+// Caller holds l.mutex
+func (l *Limiter) get(name string) *tenant {
+	t, ok := l.tenants[name]
+	if !ok {
+		t = &tenant{cfg: l.defaults}
+		l.tenants[name] = t
+	}
+	return t
+}
+
+// prune removes t once it holds and waits for nothing, so tenants seen
+// once do not stay in the map forever. Tenants set with SetTenant are kept,
+// since removing them would lose their config. Caller holds l.mutex
+func (l *Limiter) prune(name string, t *tenant) {
+	if !t.custom && t.inFlight == 0 && len(t.waiters) == 0 {
+		delete(l.tenants, name)
+	}
+}
+
+// Config returns the limit and weight name runs with
+func (l *Limiter) Config(name string) Config {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if t, ok := l.tenants[name]; ok {
+		return t.cfg
+	}
+	return l.defaults
+}
+
+// Acquire blocks until the tenant holds one tenant slot and one global slot
+// Returns:
+//   - nil once both are held, or ctx.Err() with neither held
+func (l *Limiter) Acquire(ctx context.Context, name string) error {
+	l.mutex.Lock()
+	t := l.get(name)
+	// grant runs after every change, so a free global slot means no tenant
+	// that could use it is waiting: taking it overtakes nobody
+	if l.fits(t) {
+		l.take(t)
+		l.mutex.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	t.waiters = append(t.waiters, ch)
+	l.mutex.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		select {
+		case <-ch:
+			// Granted while we were cancelling: keep the slots
+			return nil
+		default:
+		}
+		for i, w := range t.waiters {
+			if w == ch {
+				t.waiters = append(t.waiters[:i], t.waiters[i+1:]...)
+				break
+			}
+		}
+		l.prune(name, t)
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes both slots only if they are free right now
+func (l *Limiter) TryAcquire(name string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	t := l.get(name)
+	if l.fits(t) {
+		l.take(t)
+		return true
+	}
+	l.prune(name, t)
+	return false
+}
+
+// Release returns the tenant's slot and the global slot
+func (l *Limiter) Release(name string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	t, ok := l.tenants[name]
+	if !ok || t.inFlight == 0 {
+		panic("tenant: Release without Acquire for " + name)
+	}
+	t.inFlight--
+	l.inFlight--
+	l.grant()
+	l.prune(name, t)
+}
+
+// take records one grant to t. Caller holds l.mutex
+func (l *Limiter) take(t *tenant) {
+	t.inFlight++
+	t.granted++
+	l.inFlight++
+}
+
+// fits reports whether t may take a slot at both levels right now
+// Caller holds l.mutex
+func (l *Limiter) fits(t *tenant) bool {
+	return l.inFlight < l.global && t.inFlight < t.cfg.Limit
+}
+
+// grant hands free global slots to waiting tenants, fairest first:
+// among tenants that are under their own limit, the one using the least
+// of its weighted share (inFlight/weight) goes next. Caller holds l.mutex
+func (l *Limiter) grant() {
+	for l.inFlight < l.global {
+		var next *tenant
+		for _, t := range l.tenants {
+			if len(t.waiters) == 0 || t.inFlight >= t.cfg.Limit {
+				continue
+			}
+			// Compare inFlight/weight without division
+			if next == nil || t.inFlight*next.cfg.Weight < next.inFlight*t.cfg.Weight {
+				next = t
+			}
+		}
+		if next == nil {
+			return // Nobody eligible: every waiter is at its tenant limit
+		}
+		l.take(next)
+		close(next.waiters[0])
+		next.waiters = next.waiters[1:]
+	}
+}
+
+// ===================================================
+
+// ==================== STATS ====================
+// Stats describes one tenant at a point in time
+type Stats struct {
+	Config
+	InFlight int    // Slots held now
+	Waiting  int    // Blocked Acquire calls
+	Granted  uint64 // Total acquisitions
+}
+
+// Tenants returns per-tenant stats for tenants set with SetTenant or
+// holding or waiting for a slot; idle tenants have been removed
+func (l *Limiter) Tenants() map[string]Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	out := make(map[string]Stats, len(l.tenants))
+	for name, t := range l.tenants {
+		out[name] = Stats{Config: t.cfg, InFlight: t.inFlight, Waiting: len(t.waiters), Granted: t.granted}
+	}
+	return out
+}
+
+// InFlight returns the number of slots held across all tenants
+func (l *Limiter) InFlight() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.inFlight
+}
+
+// ===============================================
+
+// ==================== TENANT VIEW ====================
+// Tenant is one tenant's view of a Limiter. It satisfies pool.Limiter,
+// so pool.NewWithLimiter(ctx, l.Tenant("a")) runs a pool for tenant "a"
+// that shares the global limit with every other tenant's pool
+type Tenant struct {
+	l    *Limiter
+	name string
+}
+
+// Tenant returns the view for name
+func (l *Limiter) Tenant(name string) Tenant {
+	return Tenant{l: l, name: name}
+}
+
+func (t Tenant) Acquire(ctx context.Context) error { return t.l.Acquire(ctx, t.name) }
+func (t Tenant) Release()                          { t.l.Release(t.name) }
+
+// =====================================================
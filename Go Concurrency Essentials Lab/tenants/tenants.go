@@ -0,0 +1,215 @@
+// Go Concurrency Essentials - Per-Tenant and Global Limits
+// Description: Job runner where every tenant submits more work than it may
+//              run, limited to 5 tasks globally and 2 per tenant, with one
+//              tenant weighted to get a larger share of the global slots
+//
+// A sampler checks both limits throughout the run, a burst of jobs with
+// short deadlines checks that cancelled waiters never leak slots (and that
+// idle tenants are removed afterwards), and the final table checks that
+// each tenant's share of completed work is within -tolerance percentage
+// points of its weighted fair share. Exits 1 if any check fails
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"essentials/pool"
+	"essentials/tenant"
+)
+
+// fairSlots hands out the global slots one at a time by the limiter's own
+// rule (lowest inFlight/weight among tenants under their limit), giving
+// the steady-state split when every tenant always has work queued
+func fairSlots(global int, names []string, configs map[string]tenant.Config) map[string]int {
+	slots := make(map[string]int)
+	for range global {
+		best := ""
+		for _, name := range names {
+			cfg := configs[name]
+			if slots[name] >= cfg.Limit {
+				continue
+			}
+			if best == "" || slots[name]*configs[best].Weight < slots[best]*cfg.Weight {
+				best = name
+			}
+		}
+		if best == "" {
+			break
+		}
+		slots[best]++
+	}
+	return slots
+}
+
+// main runs every tenant's pool against one shared limiter
+func main() {
+	global := flag.Int("global", 5, "tasks running at once across all tenants")
+	perTenant := flag.Int("per-tenant", 2, "tasks running at once per tenant")
+	runFor := flag.Duration("run", 2*time.Second, "how long tenants keep submitting")
+	tolerance := flag.Float64("tolerance", 5, "allowed gap between observed and fair share, in percentage points")
+	flag.Parse()
+
+	lim := tenant.New(*global, *perTenant)
+	// "gold" may use up to 3 slots and gets 3x the share of a default tenant
+	lim.SetTenant("gold", tenant.Config{Limit: 3, Weight: 3})
+	names := []string{"gold", "alpha", "beta", "gamma"}
+
+	failed := false
+	fail := func(format string, args ...any) {
+		fmt.Printf("  FAIL: "+format+"\n", args...)
+		failed = true
+	}
+
+	// ==================== LIMIT SAMPLER ====================
+	// Tasks record their own concurrency, which the limiter cannot fake
+	var mutex sync.Mutex
+	running := make(map[string]int)
+	peak := make(map[string]int)
+	var peakGlobal, total int
+	enter := func(name string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		running[name]++
+		total++
+		peak[name] = max(peak[name], running[name])
+		peakGlobal = max(peakGlobal, total)
+	}
+	leave := func(name string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		running[name]--
+		total--
+	}
+	// =======================================================
+
+	fmt.Printf("Part 1: %d tenants saturating %d global slots (%d per tenant, gold: 3 slots, weight 3)\n",
+		len(names), *global, *perTenant)
+	ctx, cancel := context.WithTimeout(context.Background(), *runFor)
+	var wg sync.WaitGroup
+	completed := make(map[string]*atomic.Int64)
+	for _, name := range names {
+		completed[name] = new(atomic.Int64)
+		// Each tenant runs its own pool; the tenant view makes the pools
+		// share one global limit
+		workers := pool.NewWithLimiter(ctx, lim.Tenant(name))
+		// One submitter per slot keeps a waiter queued while another
+		// submitter is between grants, so a freed slot always has a taker
+		// from every tenant and the split follows the weights
+		var submitters sync.WaitGroup
+		for range lim.Config(name).Limit {
+			submitters.Go(func() {
+				for ctx.Err() == nil {
+					err := workers.Submit(ctx, func(ctx context.Context) error {
+						enter(name)
+						time.Sleep(5 * time.Millisecond)
+						leave(name)
+						completed[name].Add(1)
+						return nil
+					})
+					if err != nil {
+						break
+					}
+				}
+			})
+		}
+		wg.Go(func() {
+			submitters.Wait()
+			workers.Wait()
+		})
+	}
+	wg.Wait()
+	cancel()
+
+	var sum int64
+	for _, name := range names {
+		sum += completed[name].Load()
+	}
+	configs := make(map[string]tenant.Config)
+	for _, name := range names {
+		configs[name] = lim.Config(name)
+	}
+	expected := fairSlots(*global, names, configs)
+	fmt.Printf("  %-6s %6s %6s %9s %6s %11s\n", "tenant", "limit", "weight", "completed", "share", "fair share")
+	for _, name := range names {
+		cfg := configs[name]
+		n := completed[name].Load()
+		share := 100 * float64(n) / float64(sum)
+		fair := 100 * float64(expected[name]) / float64(*global)
+		fmt.Printf("  %-6s %6d %6d %9d %5.1f%% %10.1f%%\n", name, cfg.Limit, cfg.Weight, n, share, fair)
+		if peak[name] > cfg.Limit {
+			fail("%s ran %d tasks at once (limit %d)", name, peak[name], cfg.Limit)
+		}
+		if math.Abs(share-fair) > *tolerance {
+			fail("%s got %.1f%% of the work, fair share %.1f%% (tolerance %.1f points)",
+				name, share, fair, *tolerance)
+		}
+	}
+	fmt.Printf("  peak global concurrency %d (limit %d)\n", peakGlobal, *global)
+	if peakGlobal > *global {
+		fail("global limit exceeded")
+	}
+
+	// ==================== CANCELLATION ====================
+	fmt.Println("\nPart 2: 200 jobs with 1-20ms deadlines queued behind tasks that finish at 10ms")
+	hold, release := context.WithCancel(context.Background())
+	var holders sync.WaitGroup
+	for _, name := range names[1:] {
+		// Occupy every slot these tenants could use
+		for range *perTenant {
+			if lim.TryAcquire(name) {
+				holders.Go(func() {
+					<-hold.Done()
+					lim.Release(name)
+				})
+			}
+		}
+	}
+	time.AfterFunc(10*time.Millisecond, release)
+	var timedOut atomic.Int64
+	var jobs sync.WaitGroup
+	for i := range 200 {
+		jobs.Go(func() {
+			name := names[1+i%3]
+			deadline := time.Duration(1+i%20) * time.Millisecond
+			ctx, cancel := context.WithTimeout(context.Background(), deadline)
+			defer cancel()
+			if lim.Acquire(ctx, name) != nil {
+				timedOut.Add(1)
+				return
+			}
+			lim.Release(name)
+		})
+	}
+	jobs.Wait()
+	holders.Wait()
+
+	leaked := lim.InFlight()
+	waiting := 0
+	stats := lim.Tenants()
+	for _, st := range stats {
+		waiting += st.Waiting
+	}
+	fmt.Printf("  %d of 200 jobs timed out; afterwards %d slots held, %d waiters, %d tenant(s) kept\n",
+		timedOut.Load(), leaked, waiting, len(stats))
+	if leaked != 0 || waiting != 0 {
+		fail("cancelled waiters leaked slots")
+	}
+	// Only gold, set with SetTenant, should outlive its tasks
+	if _, ok := stats["gold"]; len(stats) != 1 || !ok {
+		fail("idle tenants not removed: %d kept", len(stats))
+	}
+	// ======================================================
+
+	if failed {
+		os.Exit(1)
+	}
+	fmt.Println("\nAll limit checks passed")
+}
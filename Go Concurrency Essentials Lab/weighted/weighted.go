@@ -0,0 +1,225 @@
+// Go Concurrency Essentials - Weighted Semaphore
+// Description: In-repo replacement for golang.org/x/sync/semaphore whose
+//              queueing policy can be chosen:
+//              - FIFO: waiters are served strictly in arrival order, so a
+//                large request at the head blocks smaller ones behind it
+//              - Barging: any request that fits is granted at once, even if
+//                older (larger) requests are waiting
+//
+// FIFO cannot starve large requests; barging gives better throughput for
+// small ones but can keep a large request waiting forever
+
+package weighted
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTooLarge is returned when a request exceeds the semaphore's size,
+// which could never be satisfied
+var ErrTooLarge = errors.New("weighted: request exceeds semaphore size")
+
+// Policy decides whether new requests may overtake queued ones
+type Policy int
+
+const (
+	FIFO    Policy = iota // Grant strictly in arrival order
+	Barging               // Grant any request that fits
+)
+
+// String returns the policy name
+func (p Policy) String() string {
+	switch p {
+	case FIFO:
+		return "fifo"
+	case Barging:
+		return "barging"
+	}
+	return "unknown"
+}
+
+// waiter is a blocked Acquire call
+type waiter struct {
+	n     int64
+	ready chan struct{} // Closed when the tokens are granted
+}
+
+// ==================== SEMAPHORE ====================
+// Semaphore is a counting semaphore whose callers acquire n tokens at once
+type Semaphore struct {
+	mutex   sync.Mutex
+	size    int64     // Total tokens
+	cur     int64     // Tokens held
+	policy  Policy    // Queueing policy
+	waiters []*waiter // Blocked Acquire calls, oldest first
+}
+
+// New creates a FIFO semaphore with n tokens
+func New(n int64) *Semaphore {
+	return NewWithPolicy(n, FIFO)
+}
+
+// NewWithPolicy creates a semaphore with n tokens and the given policy
+// Parameters:
+//   - n: Total tokens
+//   - policy: FIFO or Barging
+//
+// Returns:
+//   - Pointer to initialized semaphore
+func NewWithPolicy(n int64, policy Policy) *Semaphore {
+	return &Semaphore{size: n, policy: policy}
+}
+
+// checkN panics on a negative request, which would hand out tokens on
+// Acquire and take them back on Release
+func checkN(n int64) {
+	if n < 0 {
+		panic("weighted: negative token count")
+	}
+}
+
+// fits reports whether n tokens can be granted to a new request right now
+// Caller holds s.mutex
+func (s *Semaphore) fits(n int64) bool {
+	if s.size-s.cur < n {
+		return false
+	}
+	// Under FIFO a new request may not overtake anyone already queued
+	return s.policy == Barging || len(s.waiters) == 0
+}
+
+// Acquire blocks until n tokens are held or ctx is done
+// Panics if n is negative
+// Returns:
+//   - nil on success, ErrTooLarge if n > size, or ctx.Err()
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	checkN(n)
+	if n > s.size {
+		return ErrTooLarge
+	}
+	s.mutex.Lock()
+	if s.fits(n) {
+		s.cur += n
+		s.mutex.Unlock()
+		return nil
+	}
+	w := &waiter{n: n, ready: make(chan struct{})}
+	s.waiters = append(s.waiters, w)
+	s.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		select {
+		case <-w.ready:
+			// Granted while we were cancelling: keep the tokens
+			return nil
+		default:
+		}
+		for i, q := range s.waiters {
+			if q == w {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				break
+			}
+		}
+		// Under FIFO we may have been blocking smaller requests behind us
+		s.grant()
+		return ctx.Err()
+	}
+}
+
+// AcquireTimeout is Acquire with a deadline of d
+// Returns:
+//   - nil on success, ErrTooLarge, or context.DeadlineExceeded
+func (s *Semaphore) AcquireTimeout(n int64, d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return s.Acquire(ctx, n)
+}
+
+// TryAcquire takes n tokens only if the policy would grant them right now
+// Panics if n is negative
+func (s *Semaphore) TryAcquire(n int64) bool {
+	checkN(n)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.fits(n) {
+		return false
+	}
+	s.cur += n
+	return true
+}
+
+// Release returns n tokens and wakes waiters that now fit
+// Panics if n is negative or more than held
+func (s *Semaphore) Release(n int64) {
+	checkN(n)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("weighted: released more than held")
+	}
+	s.grant()
+}
+
+// Drain takes every token that is free right now, without waiting
+// Returns:
+//   - Number of tokens taken (release them with Release)
+func (s *Semaphore) Drain() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	n := s.size - s.cur
+	s.cur = s.size
+	return n
+}
+
+// grant hands tokens to waiters according to the policy
+// Caller holds s.mutex
+func (s *Semaphore) grant() {
+	if s.policy == FIFO {
+		// Stop at the first waiter that does not fit: no overtaking
+		for len(s.waiters) > 0 && s.size-s.cur >= s.waiters[0].n {
+			w := s.waiters[0]
+			s.cur += w.n
+			close(w.ready)
+			s.waiters = s.waiters[1:]
+		}
+		return
+	}
+
+	// Barging: serve every waiter that fits, oldest first
+	remaining := s.waiters[:0]
+	for _, w := range s.waiters {
+		if s.size-s.cur >= w.n {
+			s.cur += w.n
+			close(w.ready)
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	clear(s.waiters[len(remaining):]) // Drop references to granted waiters
+	s.waiters = remaining
+}
+
+// Available returns the number of free tokens
+func (s *Semaphore) Available() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size - s.cur
+}
+
+// Waiting returns the number of blocked Acquire calls
+func (s *Semaphore) Waiting() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.waiters)
+}
+
+// ===================================================
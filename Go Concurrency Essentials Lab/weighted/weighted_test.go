@@ -0,0 +1,169 @@
+// Go Concurrency Essentials - Weighted Semaphore Tests
+// Description: Accounting checks and the FIFO-vs-barging starvation check
+//              A pool of small clients keeps taking and returning 1 token
+//              each while a large client asks for every token. Barging must
+//              starve it; FIFO must serve it
+
+package weighted
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// panics reports whether f panics
+func panics(f func()) (did bool) {
+	defer func() { did = recover() != nil }()
+	f()
+	return false
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(cond func() bool) {
+	for !cond() {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTryAcquireAndDrain(t *testing.T) {
+	sem := New(4)
+	if !sem.TryAcquire(3) {
+		t.Fatal("TryAcquire(3) on empty semaphore failed")
+	}
+	if sem.TryAcquire(2) {
+		t.Fatal("TryAcquire(2) with 1 free succeeded")
+	}
+	if n := sem.Drain(); n != 1 {
+		t.Fatalf("Drain took %d tokens, want the last free 1", n)
+	}
+	if n := sem.Available(); n != 0 {
+		t.Fatalf("Available after Drain = %d, want 0", n)
+	}
+	sem.Release(4)
+	if err := sem.Acquire(context.Background(), 5); err != ErrTooLarge {
+		t.Fatalf("Acquire(5) of 4 = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestNegativeCountsPanic(t *testing.T) {
+	sem := New(4)
+	for name, f := range map[string]func(){
+		"Acquire":    func() { sem.Acquire(context.Background(), -1) },
+		"TryAcquire": func() { sem.TryAcquire(-1) },
+		"Release":    func() { sem.Release(-1) },
+	} {
+		if !panics(f) {
+			t.Errorf("%s(-1) did not panic", name)
+		}
+	}
+	if n := sem.Available(); n != 4 {
+		t.Errorf("Available after negative requests = %d, want 4", n)
+	}
+}
+
+func TestFIFOTryAcquireDoesNotOvertake(t *testing.T) {
+	sem := New(4)
+	sem.Acquire(context.Background(), 3)
+	go sem.Acquire(context.Background(), 4)
+	waitFor(func() bool { return sem.Waiting() == 1 })
+
+	if sem.TryAcquire(1) {
+		t.Fatal("TryAcquire(1) overtook a queued request")
+	}
+	sem.Release(3) // Grants the queued 4
+	waitFor(func() bool { return sem.Waiting() == 0 })
+	if n := sem.Available(); n != 0 {
+		t.Fatalf("Available after queued grant = %d, want 0", n)
+	}
+}
+
+// ==================== STARVATION ====================
+// outcome summarises one starvation run
+type outcome struct {
+	granted int   // Large requests that got their tokens
+	small   int64 // Small acquisitions completed during the run
+}
+
+// starve measures large requests against a steady stream of small ones
+// Parameters:
+//   - t: Test that accounting errors are reported to
+//   - policy: Queueing policy under test
+//   - size: Semaphore size (the large request asks for all of it)
+//   - smalls: Number of small clients looping Acquire(1)/Release(1)
+//   - attempts: Number of large requests, made one after another
+//   - patience: How long each large request waits before giving up
+//
+// Returns:
+//   - outcome of the run
+func starve(t *testing.T, policy Policy, size int64, smalls, attempts int, patience time.Duration) outcome {
+	sem := NewWithPolicy(size, policy)
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var small atomic.Int64
+
+	for range smalls {
+		wg.Go(func() {
+			// Acquire may succeed on a cancelled ctx, so check it each lap
+			for ctx.Err() == nil {
+				if sem.Acquire(ctx, 1) != nil {
+					return
+				}
+				time.Sleep(time.Millisecond) // Hold the token briefly
+				sem.Release(1)
+				small.Add(1)
+			}
+		})
+	}
+	time.Sleep(20 * time.Millisecond) // Let the small clients saturate it
+
+	var out outcome
+	for range attempts {
+		err := sem.AcquireTimeout(size, patience)
+		switch {
+		case err == nil:
+			out.granted++
+			time.Sleep(time.Millisecond)
+			sem.Release(size)
+		case !errors.Is(err, context.DeadlineExceeded):
+			t.Errorf("large Acquire: %v", err)
+		}
+	}
+
+	cancel()
+	wg.Wait()
+	out.small = small.Load()
+
+	// Every token must be back once all clients are gone
+	if sem.Available() != size || sem.Waiting() != 0 {
+		t.Errorf("leak: %d of %d tokens free, %d waiting", sem.Available(), size, sem.Waiting())
+	}
+	return out
+}
+
+func TestStarvation(t *testing.T) {
+	const attempts = 5
+	for _, tc := range []struct {
+		policy  Policy
+		starves bool
+	}{
+		{FIFO, false},
+		{Barging, true},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			out := starve(t, tc.policy, 10, 20, attempts, 200*time.Millisecond)
+			t.Logf("large granted %d of %d, small acquires %d", out.granted, attempts, out.small)
+			switch {
+			case !tc.starves && out.granted != attempts:
+				t.Errorf("%s starved the large request: granted %d of %d", tc.policy, out.granted, attempts)
+			case tc.starves && out.granted == attempts:
+				t.Errorf("%s did not starve the large request", tc.policy)
+			}
+		})
+	}
+}
+
+// ====================================================
@@ -0,0 +1,91 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Batch operations for SafeBuffer
+//              Consumers drain up to N items per wakeup and producers
+//              insert several items under a single lock acquisition
+
+package buffer
+
+import "time"
+
+// PutBatch adds all items in order, blocking while the buffer is full
+// Items are written in chunks: after waiting for one space, every other
+// space that is already free is taken too, and the whole chunk is stored
+// under one lock acquisition
+func (b *SafeBuffer[T]) PutBatch(items []T) {
+	for len(items) > 0 {
+		b.spaces.Wait()
+		n := 1 + tryWaitN(b.spaces, len(items)-1)
+
+		b.mutex.Lock()
+		for _, item := range items[:n] {
+			b.buffer[b.last] = item
+			b.last = (b.last + 1) % b.size
+		}
+		b.mutex.Unlock()
+
+		signalN(b.items, n)
+		items = items[n:]
+	}
+}
+
+// GetBatch removes up to max items in FIFO order
+// Waits up to timeout for the first item, then takes whatever else is
+// already available without waiting again
+// Parameters:
+//   - max: Largest number of items to return
+//   - timeout: How long to wait for the first item
+//
+// Returns:
+//   - Between 1 and max items, or nil if the timeout expired
+func (b *SafeBuffer[T]) GetBatch(max int, timeout time.Duration) []T {
+	if max <= 0 || !waitTimeout(b.items, timeout) {
+		return nil
+	}
+	n := 1 + tryWaitN(b.items, max-1)
+
+	out := make([]T, n)
+	var zero T
+	b.mutex.Lock()
+	for i := range out {
+		out[i] = b.buffer[b.first]
+		b.buffer[b.first] = zero
+		b.first = (b.first + 1) % b.size
+	}
+	b.mutex.Unlock()
+
+	signalN(b.spaces, n)
+	return out
+}
+
+// ==================== SEMAPHORE HELPERS ====================
+
+// tryWaitN takes up to n tokens without blocking and returns how many it got
+func tryWaitN(s semaphore, n int) int {
+	for got := range n {
+		select {
+		case <-s:
+		default:
+			return got
+		}
+	}
+	return n
+}
+
+// waitTimeout takes one token, giving up after timeout
+func waitTimeout(s semaphore, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-s:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// signalN returns n tokens
+func signalN(s semaphore, n int) {
+	for range n {
+		s.Signal()
+	}
+}
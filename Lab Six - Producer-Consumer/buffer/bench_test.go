@@ -0,0 +1,101 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Throughput and latency benchmarks of the bounded buffers
+//              Compares a buffered channel, the semaphore SafeBuffer and the
+//              lock-free ring at equal capacities and worker counts
+
+package buffer
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// impl names a buffer implementation and how to build one
+type impl struct {
+	name string
+	make func(size int) Buffer[int64]
+}
+
+var impls = []impl{
+	{"channel", func(n int) Buffer[int64] { return NewChanBuffer[int64](n) }},
+	{"SafeBuffer", func(n int) Buffer[int64] { return NewSafeBuffer[int64](n) }},
+	{"RingBuffer", func(n int) Buffer[int64] { return NewRingBuffer[int64](n) }},
+}
+
+// Sizes are powers of two, so the ring (which rounds its capacity up) holds
+// exactly as many items as the channel and SafeBuffer it is compared with
+var (
+	benchWorkers = []int{1, 4, 16}
+	benchSizes   = []int{16, 1024}
+)
+
+// measure pushes items through buf with the given number of producers and
+// consumers. Each item carries its enqueue time (nanoseconds since start),
+// so consumers can compute how long it sat in the buffer
+// Parameters:
+//   - buf: Buffer under test
+//   - producers, consumers: Goroutine counts
+//   - items: Total items to transfer (rounded up to a multiple of producers)
+//
+// Returns:
+//   - Put-to-Get latency of every item, sorted
+func measure(buf Buffer[int64], producers, consumers, items int) []time.Duration {
+	perProducer := (items + producers - 1) / producers
+	total := perProducer * producers
+	latencies := make([][]time.Duration, consumers)
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for range producers {
+		wg.Go(func() {
+			for range perProducer {
+				buf.Put(int64(time.Since(start)))
+			}
+		})
+	}
+
+	for c := range consumers {
+		share := total / consumers
+		if c < total%consumers {
+			share++
+		}
+		latencies[c] = make([]time.Duration, 0, share)
+		wg.Go(func() {
+			for range share {
+				enqueued := time.Duration(buf.Get())
+				latencies[c] = append(latencies[c], time.Since(start)-enqueued)
+			}
+		})
+	}
+
+	wg.Wait()
+	all := slices.Concat(latencies...)
+	slices.Sort(all)
+	return all
+}
+
+// BenchmarkBuffers runs the same number of producers and consumers for each
+// worker count; one op is one item transferred. Latency is the time an item
+// spends between Put and Get, reported as p50-ns and p99-ns
+func BenchmarkBuffers(b *testing.B) {
+	for _, w := range benchWorkers {
+		for _, size := range benchSizes {
+			for _, im := range impls {
+				b.Run(fmt.Sprintf("%s/workers=%d/size=%d", im.name, w, size), func(b *testing.B) {
+					buf := im.make(size)
+					if r, ok := buf.(*RingBuffer[int64]); ok && r.Cap() != size {
+						b.Fatalf("ring capacity %d, want %d", r.Cap(), size)
+					}
+					b.ResetTimer()
+					all := measure(buf, w, w, b.N)
+					b.StopTimer()
+					b.ReportMetric(float64(all[len(all)*50/100]), "p50-ns")
+					b.ReportMetric(float64(all[len(all)*99/100]), "p99-ns")
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Bounded buffer implementations shared by the Go producer-consumer
+//              programs. SafeBuffer mirrors the C++ SafeBuffer.h design
+
+package buffer
+
+import "sync"
+
+// ==================== BUFFER INTERFACE ====================
+// Buffer is a bounded FIFO queue shared between producers and consumers
+// Put blocks while the buffer is full, Get blocks while it is empty
+type Buffer[T any] interface {
+	Put(item T)
+	Get() T
+}
+
+// ==========================================================
+
+// semaphore is a counting semaphore built from a buffered channel
+// Tokens sitting in the channel are the semaphore's current value
+type semaphore chan struct{}
+
+// newSemaphore creates a semaphore with the given initial and maximum value
+func newSemaphore(initial, max int) semaphore {
+	s := make(semaphore, max)
+	for range initial {
+		s <- struct{}{}
+	}
+	return s
+}
+
+// Wait takes a token, blocking while the value is zero
+func (s semaphore) Wait() {
+	<-s
+}
+
+// Signal returns a token, waking one blocked Wait
+func (s semaphore) Signal() {
+	s <- struct{}{}
+}
+
+// ==================== SAFE BUFFER ====================
+// SafeBuffer is a circular buffer guarded by a mutex and two counting
+// semaphores, exactly as in the C++ SafeBuffer.h:
+//   - spaces: counts free slots (blocks producers when full)
+//   - items:  counts filled slots (blocks consumers when empty)
+type SafeBuffer[T any] struct {
+	mutex  sync.Mutex // Protects buffer, first and last
+	buffer []T        // Circular array storage
+	size   int        // Buffer capacity
+	first  int        // Index of first item (for Get)
+	last   int        // Index where next item goes (for Put)
+	spaces semaphore  // Counts available spaces
+	items  semaphore  // Counts items in buffer
+}
+
+// NewSafeBuffer creates an empty SafeBuffer
+// Parameters:
+//   - size: Buffer capacity
+//
+// Returns:
+//   - Pointer to initialized buffer
+func NewSafeBuffer[T any](size int) *SafeBuffer[T] {
+	return &SafeBuffer[T]{
+		buffer: make([]T, size),
+		size:   size,
+		spaces: newSemaphore(size, size), // All spaces initially free
+		items:  newSemaphore(0, size),    // No items initially
+	}
+}
+
+// Put adds an item to the buffer, blocking if the buffer is full
+func (b *SafeBuffer[T]) Put(item T) {
+	b.spaces.Wait() // Wait for available space
+	b.mutex.Lock()
+
+	b.buffer[b.last] = item
+	b.last = (b.last + 1) % b.size // Wrap around using modulo
+
+	b.mutex.Unlock()
+	b.items.Signal() // Signal that item is available for consumers
+}
+
+// Get removes and returns the oldest item, blocking if the buffer is empty
+func (b *SafeBuffer[T]) Get() T {
+	b.items.Wait() // Wait for item to be available
+	b.mutex.Lock()
+
+	item := b.buffer[b.first]
+	var zero T
+	b.buffer[b.first] = zero         // Drop reference so it can be collected
+	b.first = (b.first + 1) % b.size // Wrap around using modulo
+
+	b.mutex.Unlock()
+	b.spaces.Signal() // Signal that space is available for producers
+	return item
+}
+
+// ==================== CHANNEL BUFFER ====================
+// ChanBuffer is the idiomatic Go bounded buffer: a buffered channel
+type ChanBuffer[T any] struct {
+	ch chan T
+}
+
+// NewChanBuffer creates a channel-backed buffer with the given capacity
+func NewChanBuffer[T any](size int) *ChanBuffer[T] {
+	return &ChanBuffer[T]{ch: make(chan T, size)}
+}
+
+// Put sends an item, blocking if the channel is full
+func (b *ChanBuffer[T]) Put(item T) {
+	b.ch <- item
+}
+
+// Get receives an item, blocking if the channel is empty
+func (b *ChanBuffer[T]) Get() T {
+	return <-b.ch
+}
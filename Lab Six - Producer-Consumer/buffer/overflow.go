@@ -0,0 +1,245 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Bounded buffer with configurable overflow policies
+//              SafeBuffer always blocks producers when full; telemetry
+//              pipelines often prefer to drop data or fail fast instead
+
+package buffer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ==================== OVERFLOW POLICIES ====================
+// Policy decides what Offer does when the buffer is full
+type Policy int
+
+const (
+	Block        Policy = iota // Wait for space (SafeBuffer behaviour)
+	DropNewest                 // Discard the item being offered
+	DropOldest                 // Evict the oldest buffered item to make room
+	Reject                     // Return ErrFull immediately
+	BlockTimeout               // Wait for space, up to a timeout, then ErrTimeout
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropNewest:
+		return "drop-newest"
+	case DropOldest:
+		return "drop-oldest"
+	case Reject:
+		return "reject"
+	case BlockTimeout:
+		return "block-timeout"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+// Errors returned by Offer and GetTimeout
+var (
+	ErrFull    = errors.New("buffer: full")
+	ErrTimeout = errors.New("buffer: timed out")
+)
+
+// ===========================================================
+
+// Stats counts what happened to offered items
+// Offered == Accepted + DroppedNewest + Rejected + TimedOut, and
+// EvictedOldest of the accepted items never reach a consumer
+type Stats struct {
+	Offered       uint64 // Calls to Offer/Put
+	Accepted      uint64 // Items stored in the buffer
+	DroppedNewest uint64 // Items discarded by DropNewest
+	EvictedOldest uint64 // Buffered items discarded by DropOldest
+	Rejected      uint64 // Offers that returned ErrFull
+	TimedOut      uint64 // Offers that returned ErrTimeout
+}
+
+// Dropped returns the total number of items lost by the policy
+func (s Stats) Dropped() uint64 {
+	return s.DroppedNewest + s.EvictedOldest + s.Rejected + s.TimedOut
+}
+
+// ==================== POLICY BUFFER ====================
+// PolicyBuffer is a SafeBuffer whose Put behaviour on overflow is chosen
+// at construction time. Get always blocks while the buffer is empty
+type PolicyBuffer[T any] struct {
+	mutex   sync.Mutex    // Protects buffer, first and last
+	buffer  []T           // Circular array storage
+	size    int           // Buffer capacity
+	first   int           // Index of first item (for Get)
+	last    int           // Index where next item goes (for Put)
+	spaces  semaphore     // Counts available spaces
+	items   semaphore     // Counts items in buffer
+	policy  Policy        // Behaviour when full
+	timeout time.Duration // Wait limit for BlockTimeout
+
+	offered, accepted, droppedNewest, evictedOldest, rejected, timedOut atomic.Uint64
+}
+
+// NewPolicyBuffer creates an empty buffer with the given overflow policy
+// Parameters:
+//   - size: Buffer capacity
+//   - policy: Behaviour when full
+//   - timeout: Maximum wait for BlockTimeout (ignored by other policies)
+//
+// Returns:
+//   - Pointer to initialized buffer
+func NewPolicyBuffer[T any](size int, policy Policy, timeout time.Duration) *PolicyBuffer[T] {
+	return &PolicyBuffer[T]{
+		buffer:  make([]T, size),
+		size:    size,
+		spaces:  newSemaphore(size, size),
+		items:   newSemaphore(0, size),
+		policy:  policy,
+		timeout: timeout,
+	}
+}
+
+// Offer adds an item according to the buffer's policy
+// Returns:
+//   - nil if the item was stored or deliberately dropped (DropNewest)
+//   - ErrFull under Reject, ErrTimeout under BlockTimeout
+func (b *PolicyBuffer[T]) Offer(item T) error {
+	b.offered.Add(1)
+
+	switch b.policy {
+	case DropNewest:
+		select {
+		case <-b.spaces:
+		default:
+			b.droppedNewest.Add(1)
+			return nil
+		}
+
+	case DropOldest:
+		if !b.evictForSpace() {
+			// Evicted the oldest item: reuse its slot for the new one
+			b.evictedOldest.Add(1)
+			b.mutex.Lock()
+			var zero T
+			b.buffer[b.first] = zero
+			b.first = (b.first + 1) % b.size
+			b.store(item)
+			b.mutex.Unlock()
+			b.accepted.Add(1)
+			b.items.Signal()
+			return nil
+		}
+
+	case Reject:
+		select {
+		case <-b.spaces:
+		default:
+			b.rejected.Add(1)
+			return ErrFull
+		}
+
+	case BlockTimeout:
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+		select {
+		case <-b.spaces:
+		case <-timer.C:
+			b.timedOut.Add(1)
+			return ErrTimeout
+		}
+
+	default: // Block
+		b.spaces.Wait()
+	}
+
+	b.mutex.Lock()
+	b.store(item)
+	b.mutex.Unlock()
+	b.accepted.Add(1)
+	b.items.Signal()
+	return nil
+}
+
+// evictForSpace obtains either a free space (returns true) or an item
+// token whose slot the caller must evict (returns false). A free space is
+// preferred; if there is neither, which only happens while a consumer is
+// between taking an item and returning its space, it blocks on both
+// semaphores until that consumer signals
+func (b *PolicyBuffer[T]) evictForSpace() bool {
+	select {
+	case <-b.spaces:
+		return true
+	default:
+	}
+	select {
+	case <-b.spaces:
+		return true
+	case <-b.items:
+		return false
+	}
+}
+
+// store writes item at the tail. Caller holds b.mutex
+func (b *PolicyBuffer[T]) store(item T) {
+	b.buffer[b.last] = item
+	b.last = (b.last + 1) % b.size
+}
+
+// Put offers an item and discards any error, satisfying Buffer[T]
+func (b *PolicyBuffer[T]) Put(item T) {
+	_ = b.Offer(item)
+}
+
+// Get removes and returns the oldest item, blocking if the buffer is empty
+func (b *PolicyBuffer[T]) Get() T {
+	b.items.Wait()
+	return b.take()
+}
+
+// GetTimeout is Get with a wait limit
+// Returns:
+//   - The oldest item, or ErrTimeout if none arrived within d
+func (b *PolicyBuffer[T]) GetTimeout(d time.Duration) (T, error) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-b.items:
+		return b.take(), nil
+	case <-timer.C:
+		var zero T
+		return zero, ErrTimeout
+	}
+}
+
+// take removes the head item once the caller holds an items token
+func (b *PolicyBuffer[T]) take() T {
+	b.mutex.Lock()
+	item := b.buffer[b.first]
+	var zero T
+	b.buffer[b.first] = zero
+	b.first = (b.first + 1) % b.size
+	b.mutex.Unlock()
+
+	b.spaces.Signal()
+	return item
+}
+
+// Policy returns the buffer's overflow policy
+func (b *PolicyBuffer[T]) Policy() Policy {
+	return b.policy
+}
+
+// Stats returns a snapshot of the buffer's counters
+func (b *PolicyBuffer[T]) Stats() Stats {
+	return Stats{
+		Offered:       b.offered.Load(),
+		Accepted:      b.accepted.Load(),
+		DroppedNewest: b.droppedNewest.Load(),
+		EvictedOldest: b.evictedOldest.Load(),
+		Rejected:      b.rejected.Load(),
+		TimedOut:      b.timedOut.Load(),
+	}
+}
@@ -0,0 +1,207 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Bounded buffer with priority lanes
+//              Each lane is its own circular buffer with its own spaces
+//              semaphore; a shared items semaphore wakes consumers, which
+//              then choose a lane by strict priority or weighted-fair order
+
+package buffer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ==================== DEQUEUE MODES ====================
+// Mode selects how Get chooses between non-empty lanes
+type Mode int
+
+const (
+	// Strict always serves the lowest-numbered non-empty lane, so a busy
+	// high-priority lane can starve the others indefinitely
+	Strict Mode = iota
+	// Weighted shares service between non-empty lanes in proportion to
+	// their weights (smooth weighted round-robin), so no lane starves
+	Weighted
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Strict:
+		return "strict"
+	case Weighted:
+		return "weighted"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// =======================================================
+
+// lane is one priority level: a circular buffer plus scheduling state
+type lane[T any] struct {
+	buffer  []T       // Circular array storage
+	first   int       // Index of first item
+	count   int       // Items currently buffered
+	spaces  semaphore // Counts available spaces in this lane
+	weight  int       // Share of service under Weighted
+	current int       // Smooth weighted round-robin credit
+	served  uint64    // Items handed to consumers
+}
+
+// ==================== PRIORITY BUFFER ====================
+// PriorityBuffer is a bounded buffer with several lanes
+// Lane 0 is the highest priority
+type PriorityBuffer[T any] struct {
+	mutex sync.Mutex // Protects all lanes
+	lanes []*lane[T] // One circular buffer per priority
+	items semaphore  // Counts items across all lanes
+	mode  Mode       // Lane selection policy
+}
+
+// NewPriorityBuffer creates a buffer with one lane per weight
+// Parameters:
+//   - size: Capacity of each lane
+//   - mode: Strict or Weighted lane selection
+//   - weights: One weight per lane, lane 0 first (ignored by Strict,
+//     but the number of weights sets the number of lanes)
+//
+// Returns:
+//   - Pointer to initialized buffer
+func NewPriorityBuffer[T any](size int, mode Mode, weights ...int) *PriorityBuffer[T] {
+	b := &PriorityBuffer[T]{
+		items: newSemaphore(0, size*len(weights)),
+		mode:  mode,
+	}
+	for _, w := range weights {
+		b.lanes = append(b.lanes, &lane[T]{
+			buffer: make([]T, size),
+			spaces: newSemaphore(size, size),
+			weight: max(w, 1),
+		})
+	}
+	return b
+}
+
+// Put adds an item to a lane, blocking while that lane is full
+func (b *PriorityBuffer[T]) Put(laneID int, item T) {
+	b.PutBatch(laneID, []T{item})
+}
+
+// PutBatch adds items to a lane in order, blocking while that lane is full
+// Like SafeBuffer.PutBatch, each lock acquisition stores as many items as
+// there are free spaces
+func (b *PriorityBuffer[T]) PutBatch(laneID int, items []T) {
+	l := b.lanes[laneID]
+	for len(items) > 0 {
+		l.spaces.Wait()
+		n := 1 + tryWaitN(l.spaces, len(items)-1)
+
+		b.mutex.Lock()
+		for _, item := range items[:n] {
+			l.buffer[(l.first+l.count)%len(l.buffer)] = item
+			l.count++
+		}
+		b.mutex.Unlock()
+
+		signalN(b.items, n)
+		items = items[n:]
+	}
+}
+
+// Get removes one item, blocking while every lane is empty
+// Returns:
+//   - The item and the lane it came from
+func (b *PriorityBuffer[T]) Get() (T, int) {
+	b.items.Wait()
+
+	b.mutex.Lock()
+	laneID := b.pick()
+	item := b.take(laneID)
+	b.mutex.Unlock()
+
+	b.lanes[laneID].spaces.Signal()
+	return item, laneID
+}
+
+// Item is an item returned by GetBatch together with its lane
+type Item[T any] struct {
+	Lane  int
+	Value T
+}
+
+// GetBatch removes up to max items, choosing a lane for each one exactly
+// as Get would. Waits up to timeout for the first item only
+// Returns:
+//   - Between 1 and max items, or nil if the timeout expired
+func (b *PriorityBuffer[T]) GetBatch(max int, timeout time.Duration) []Item[T] {
+	if max <= 0 || !waitTimeout(b.items, timeout) {
+		return nil
+	}
+	n := 1 + tryWaitN(b.items, max-1)
+
+	out := make([]Item[T], n)
+	b.mutex.Lock()
+	for i := range out {
+		laneID := b.pick()
+		out[i] = Item[T]{Lane: laneID, Value: b.take(laneID)}
+	}
+	b.mutex.Unlock()
+
+	for _, it := range out {
+		b.lanes[it.Lane].spaces.Signal()
+	}
+	return out
+}
+
+// pick chooses the lane to serve next. Caller holds b.mutex and an
+// items token, so at least one lane is non-empty
+func (b *PriorityBuffer[T]) pick() int {
+	if b.mode == Strict {
+		for i, l := range b.lanes {
+			if l.count > 0 {
+				return i
+			}
+		}
+	}
+
+	// Smooth weighted round-robin over the non-empty lanes: every lane
+	// earns its weight in credit, the richest is served and pays back the
+	// total. Over any window each lane is served in proportion to weight
+	best, total := -1, 0
+	for i, l := range b.lanes {
+		if l.count == 0 {
+			continue
+		}
+		l.current += l.weight
+		total += l.weight
+		if best < 0 || l.current > b.lanes[best].current {
+			best = i
+		}
+	}
+	b.lanes[best].current -= total
+	return best
+}
+
+// take removes the head item of a lane. Caller holds b.mutex
+func (b *PriorityBuffer[T]) take(laneID int) T {
+	l := b.lanes[laneID]
+	item := l.buffer[l.first]
+	var zero T
+	l.buffer[l.first] = zero
+	l.first = (l.first + 1) % len(l.buffer)
+	l.count--
+	l.served++
+	return item
+}
+
+// Served returns how many items each lane has handed to consumers
+func (b *PriorityBuffer[T]) Served() []uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	out := make([]uint64, len(b.lanes))
+	for i, l := range b.lanes {
+		out[i] = l.served
+	}
+	return out
+}
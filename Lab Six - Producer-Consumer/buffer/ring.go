@@ -0,0 +1,143 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Lock-free multi-producer multi-consumer bounded queue
+//              Dmitry Vyukov's sequence-numbered ring, built on sync/atomic
+//
+// Each cell carries a sequence number that tells producers and consumers
+// whose turn it is:
+//   - seq == pos     cell is free for the producer claiming position pos
+//   - seq == pos+1   cell holds the item for the consumer claiming pos
+// Claiming a position is a single CompareAndSwap on enqueue/dequeue, so no
+// goroutine ever holds a lock another goroutine has to wait for
+
+package buffer
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// cacheLinePad keeps hot atomics on separate cache lines
+type cacheLinePad [64]byte
+
+// cell is one slot of the ring
+type cell[T any] struct {
+	seq  atomic.Uint64 // Turn indicator (see file comment)
+	item T             // Payload, owned by whoever won the turn
+}
+
+// ==================== RING BUFFER ====================
+// RingBuffer is a lock-free bounded MPMC queue
+// Capacity is rounded up to the next power of two
+type RingBuffer[T any] struct {
+	_       cacheLinePad
+	enqueue atomic.Uint64 // Next position producers will claim
+	_       cacheLinePad
+	dequeue atomic.Uint64 // Next position consumers will claim
+	_       cacheLinePad
+	mask    uint64    // Capacity - 1, for cheap modulo
+	cells   []cell[T] // Ring storage
+}
+
+// NewRingBuffer creates an empty lock-free ring
+// Parameters:
+//   - size: Minimum capacity (rounded up to a power of two, at least 2)
+//
+// Returns:
+//   - Pointer to initialized ring
+func NewRingBuffer[T any](size int) *RingBuffer[T] {
+	capacity := 2
+	for capacity < size {
+		capacity <<= 1
+	}
+
+	r := &RingBuffer[T]{
+		mask:  uint64(capacity - 1),
+		cells: make([]cell[T], capacity),
+	}
+	// Cell i is initially free for the producer claiming position i
+	for i := range r.cells {
+		r.cells[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+// TryPut adds an item without blocking
+// Returns:
+//   - false if the ring is full
+func (r *RingBuffer[T]) TryPut(item T) bool {
+	pos := r.enqueue.Load()
+	for {
+		c := &r.cells[pos&r.mask]
+		seq := c.seq.Load()
+
+		switch diff := int64(seq) - int64(pos); {
+		case diff == 0:
+			// Cell is free for this position: try to claim it
+			if r.enqueue.CompareAndSwap(pos, pos+1) {
+				c.item = item
+				c.seq.Store(pos + 1) // Publish to the consumer of pos
+				return true
+			}
+			pos = r.enqueue.Load() // Another producer won, retry
+		case diff < 0:
+			// Cell still holds the item from one lap ago: ring is full
+			return false
+		default:
+			// Another producer already claimed pos, catch up
+			pos = r.enqueue.Load()
+		}
+	}
+}
+
+// TryGet removes the oldest item without blocking
+// Returns:
+//   - The item and true, or the zero value and false if the ring is empty
+func (r *RingBuffer[T]) TryGet() (T, bool) {
+	pos := r.dequeue.Load()
+	for {
+		c := &r.cells[pos&r.mask]
+		seq := c.seq.Load()
+
+		switch diff := int64(seq) - int64(pos+1); {
+		case diff == 0:
+			// Cell holds the item for this position: try to claim it
+			if r.dequeue.CompareAndSwap(pos, pos+1) {
+				item := c.item
+				var zero T
+				c.item = zero
+				c.seq.Store(pos + r.mask + 1) // Free the cell for the next lap
+				return item, true
+			}
+			pos = r.dequeue.Load() // Another consumer won, retry
+		case diff < 0:
+			// Producer has not published this position yet: ring is empty
+			var zero T
+			return zero, false
+		default:
+			// Another consumer already claimed pos, catch up
+			pos = r.dequeue.Load()
+		}
+	}
+}
+
+// Put adds an item, spinning (and yielding the processor) while full
+func (r *RingBuffer[T]) Put(item T) {
+	for !r.TryPut(item) {
+		runtime.Gosched()
+	}
+}
+
+// Get removes the oldest item, spinning (and yielding the processor) while empty
+func (r *RingBuffer[T]) Get() T {
+	for {
+		if item, ok := r.TryGet(); ok {
+			return item
+		}
+		runtime.Gosched()
+	}
+}
+
+// Cap returns the ring's capacity
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.cells)
+}
@@ -0,0 +1,247 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Event type and exactly-once delivery verifier
+//              Collects every consumed event and proves that no event was
+//              lost, duplicated or (per producer) reordered
+
+package event
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ==================== EVENT ====================
+// Event is produced by one producer and consumed by one consumer
+// (Producer, Seq) uniquely identifies an event within a run
+type Event struct {
+	Producer int // ID of the producer that created the event
+	Seq      int // Per-producer sequence number, starting at 0
+}
+
+// ID returns the C++ style event ID (producer * 1000 + seq)
+// IDs are only unique while each producer creates at most 1000 events
+func (e Event) ID() int {
+	return e.Producer*1000 + e.Seq
+}
+
+// String formats the event as producer/seq
+func (e Event) String() string {
+	return fmt.Sprintf("P%d#%d", e.Producer, e.Seq)
+}
+
+// ===============================================
+
+// ==================== VIOLATIONS ====================
+// Kind classifies a delivery violation
+type Kind int
+
+const (
+	Lost       Kind = iota // Produced but never consumed
+	Duplicated             // Consumed more than once
+	Reordered              // Consumed before an earlier event of the same producer
+	Unknown                // Consumed but never produced
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Lost:
+		return "lost"
+	case Duplicated:
+		return "duplicated"
+	case Reordered:
+		return "reordered"
+	case Unknown:
+		return "unknown"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Violation describes one event that broke exactly-once, in-order delivery
+type Violation struct {
+	Kind      Kind  // What went wrong
+	Event     Event // The offending event
+	Consumers []int // Consumers that received it (empty when lost)
+	Detail    string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%-10s %-10s consumers=%v %s", v.Kind, v.Event, v.Consumers, v.Detail)
+}
+
+// ====================================================
+
+// ==================== VERIFIER ====================
+// Verifier records every consumed event and checks the run afterwards
+//
+// Ordering is checked per consumer: a FIFO buffer hands a single consumer
+// the events of one producer in the order they were put, so within one
+// consumer's stream a producer's Seq must strictly increase. Comparing
+// across consumers would report false reorderings, because consumers
+// record after Get returns and may be descheduled in between.
+type Verifier struct {
+	mutex       sync.Mutex
+	producers   int             // Number of producers in the run
+	perProducer int             // Events each producer creates
+	seen        map[Event][]int // Consumers that received each event
+	lastSeq     map[[2]int]int  // (consumer, producer) -> last Seq seen
+	reordered   []Violation     // Reorderings found while recording
+	consumed    int             // Total events recorded
+}
+
+// NewVerifier creates a verifier for a run where each of producers
+// producers creates perProducer events numbered 0..perProducer-1
+func NewVerifier(producers, perProducer int) *Verifier {
+	return &Verifier{
+		producers:   producers,
+		perProducer: perProducer,
+		seen:        make(map[Event][]int),
+		lastSeq:     make(map[[2]int]int),
+	}
+}
+
+// Record notes that consumer received e. Safe for concurrent use
+func (v *Verifier) Record(consumer int, e Event) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.consumed++
+	v.seen[e] = append(v.seen[e], consumer)
+
+	key := [2]int{consumer, e.Producer}
+	if last, ok := v.lastSeq[key]; ok && e.Seq < last {
+		v.reordered = append(v.reordered, Violation{
+			Kind:      Reordered,
+			Event:     e,
+			Consumers: []int{consumer},
+			Detail:    fmt.Sprintf("after %s", Event{Producer: e.Producer, Seq: last}),
+		})
+	}
+	v.lastSeq[key] = e.Seq
+}
+
+// Report is the outcome of a verified run
+type Report struct {
+	Produced   int         // Events the producers were expected to create
+	Consumed   int         // Events recorded by consumers
+	Violations []Violation // Every problem found, sorted by event
+}
+
+// OK reports whether every event was delivered exactly once and in order
+func (r Report) OK() bool {
+	return len(r.Violations) == 0
+}
+
+// String prints a summary line followed by one line per violation
+func (r Report) String() string {
+	var sb strings.Builder
+	status := "OK"
+	if !r.OK() {
+		status = "FAILED"
+	}
+	fmt.Fprintf(&sb, "%s: produced=%d consumed=%d violations=%d\n",
+		status, r.Produced, r.Consumed, len(r.Violations))
+	for _, v := range r.Violations {
+		fmt.Fprintf(&sb, "  %s\n", v)
+	}
+	return sb.String()
+}
+
+// Verify checks the recorded events against what should have been produced
+func (v *Verifier) Verify() Report {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	report := Report{
+		Produced: v.producers * v.perProducer,
+		Consumed: v.consumed,
+	}
+
+	// Every expected event must have been consumed exactly once
+	for p := range v.producers {
+		for s := range v.perProducer {
+			e := Event{Producer: p, Seq: s}
+			switch consumers := v.seen[e]; {
+			case len(consumers) == 0:
+				report.Violations = append(report.Violations, Violation{Kind: Lost, Event: e})
+			case len(consumers) > 1:
+				report.Violations = append(report.Violations, Violation{
+					Kind:      Duplicated,
+					Event:     e,
+					Consumers: consumers,
+					Detail:    fmt.Sprintf("received %d times", len(consumers)),
+				})
+			}
+		}
+	}
+
+	// Anything outside the expected range was never produced
+	for e, consumers := range v.seen {
+		if e.Producer < 0 || e.Producer >= v.producers || e.Seq < 0 || e.Seq >= v.perProducer {
+			report.Violations = append(report.Violations, Violation{Kind: Unknown, Event: e, Consumers: consumers})
+		}
+	}
+
+	report.Violations = append(report.Violations, v.reordered...)
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		a, b := report.Violations[i], report.Violations[j]
+		if a.Event.Producer != b.Event.Producer {
+			return a.Event.Producer < b.Event.Producer
+		}
+		if a.Event.Seq != b.Event.Seq {
+			return a.Event.Seq < b.Event.Seq
+		}
+		return a.Kind < b.Kind
+	})
+	return report
+}
+
+// ==================================================
+
+// ==================== RUN HARNESS ====================
+// Queue is any bounded buffer of events (buffer.Buffer[Event] satisfies it)
+type Queue interface {
+	Put(item Event)
+	Get() Event
+}
+
+// Run drives producers and consumers against q and verifies the result
+// Each producer puts perProducer events; consumers share the total
+// evenly (any remainder goes to the first consumers)
+// Parameters:
+//   - q: Buffer implementation under test
+//   - producers: Number of producer goroutines
+//   - consumers: Number of consumer goroutines
+//   - perProducer: Events created by each producer
+//
+// Returns:
+//   - Report describing any lost, duplicated or reordered events
+func Run(q Queue, producers, consumers, perProducer int) Report {
+	verifier := NewVerifier(producers, perProducer)
+	total := producers * perProducer
+	var wg sync.WaitGroup
+
+	for p := range producers {
+		wg.Go(func() {
+			for s := range perProducer {
+				q.Put(Event{Producer: p, Seq: s})
+			}
+		})
+	}
+
+	for c := range consumers {
+		share := total / consumers
+		if c < total%consumers {
+			share++
+		}
+		wg.Go(func() {
+			for range share {
+				verifier.Record(c, q.Get())
+			}
+		})
+	}
+
+	wg.Wait()
+	return verifier.Verify()
+}
@@ -0,0 +1,88 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Runs producers and consumers against each bounded buffer
+//              implementation and verifies exactly-once, in-order delivery
+//
+// Configuration mirrors main.cpp:
+// - 50 producers + 50 consumers
+// - Buffer capacity: 20 events
+// - Each producer creates 10 events
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"prodcon/buffer"
+	"prodcon/event"
+)
+
+// ==================== CONFIGURATION ====================
+const (
+	numThreads = 100 // Total goroutines (producers + consumers)
+	size       = 20  // Buffer capacity
+	numLoops   = 10  // Items per producer
+)
+
+// =======================================================
+
+// faultyBuffer wraps a buffer and corrupts every nth event it hands out
+// Used to show what the verifier reports when a buffer is broken
+type faultyBuffer struct {
+	event.Queue
+	mutex sync.Mutex
+	n     int         // Corrupt every nth Get
+	gets  int         // Number of Gets so far
+	prev  event.Event // Last event handed out
+}
+
+// Get returns the previous event instead of the real one every nth call,
+// which both duplicates one event and loses another
+func (f *faultyBuffer) Get() event.Event {
+	e := f.Queue.Get()
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.gets++
+	if f.gets%f.n == 0 {
+		e = f.prev
+	}
+	f.prev = e
+	return e
+}
+
+// main verifies every buffer implementation in turn
+func main() {
+	inject := flag.Int("inject", 0, "corrupt every nth event to demonstrate violation reports (0 = off)")
+	flag.Parse()
+
+	buffers := []struct {
+		name string
+		q    event.Queue
+	}{
+		{"SafeBuffer", buffer.NewSafeBuffer[event.Event](size)},
+		{"ChanBuffer", buffer.NewChanBuffer[event.Event](size)},
+		{"RingBuffer", buffer.NewRingBuffer[event.Event](size)},
+	}
+
+	failed := false
+	for _, b := range buffers {
+		q := b.q
+		if *inject > 0 {
+			q = &faultyBuffer{Queue: q, n: *inject}
+		}
+
+		fmt.Printf("%s (%d producers, %d consumers, %d events each)\n",
+			b.name, numThreads/2, numThreads/2, numLoops)
+		report := event.Run(q, numThreads/2, numThreads/2, numLoops)
+		fmt.Print(report)
+		failed = failed || !report.OK()
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	fmt.Println("\nAll events delivered exactly once and in order!")
+}
@@ -0,0 +1,3 @@
+module prodcon
+
+go 1.25.3
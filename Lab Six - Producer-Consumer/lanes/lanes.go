@@ -0,0 +1,141 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Priority lanes and batch consumption
+//              Keeps every lane saturated and checks whether the
+//              low-priority lane starves under strict and weighted modes
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"prodcon/buffer"
+)
+
+// ==================== CONFIGURATION ====================
+const (
+	laneSize    = 64                     // Capacity of each lane
+	putBatch    = 16                     // Items per producer PutBatch
+	getBatch    = 8                      // Maximum items per consumer wakeup
+	numServed   = 8000                   // Items the consumer measures per mode
+	processCost = 100 * time.Microsecond // Consumer work per batch (slower than producers)
+)
+
+var (
+	laneNames = []string{"high", "normal", "low"}
+	weights   = []int{6, 3, 1} // Weighted mode shares: 60% / 30% / 10%
+)
+
+// =======================================================
+
+// outcome records how one mode treated the lanes
+type outcome struct {
+	served  []int // Items served per lane during the measured window
+	maxGap  int   // Longest run of items served without touching the low lane
+	wakeups int   // GetBatch calls that returned items
+}
+
+// run saturates every lane and consumes numServed items in batches
+// Parameters:
+//   - mode: Strict or Weighted lane selection
+//
+// Returns:
+//   - Per-lane service counts and the low lane's longest wait
+func run(mode buffer.Mode) outcome {
+	buf := buffer.NewPriorityBuffer[int](laneSize, mode, weights...)
+	low := len(weights) - 1
+	var stop atomic.Bool
+	var producers sync.WaitGroup
+
+	// One producer per lane, always trying to keep its lane full
+	for laneID := range weights {
+		producers.Go(func() {
+			batch := make([]int, putBatch)
+			for !stop.Load() {
+				buf.PutBatch(laneID, batch)
+			}
+		})
+	}
+
+	// Let the producers fill every lane before measuring
+	time.Sleep(10 * time.Millisecond)
+
+	res := outcome{served: make([]int, len(weights))}
+	gap := 0
+	for total := 0; total < numServed; {
+		items := buf.GetBatch(min(getBatch, numServed-total), time.Second)
+		res.wakeups++
+		for _, it := range items {
+			res.served[it.Lane]++
+			if it.Lane == low {
+				gap = 0
+			} else {
+				gap++
+				res.maxGap = max(res.maxGap, gap)
+			}
+		}
+		total += len(items)
+		time.Sleep(processCost)
+	}
+
+	// Stop producers, draining so none stays blocked on a full lane
+	stop.Store(true)
+	done := make(chan struct{})
+	go func() {
+		producers.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return res
+		default:
+			buf.GetBatch(laneSize, time.Millisecond)
+		}
+	}
+}
+
+// main compares the two modes and checks the weighted mode for starvation
+func main() {
+	totalWeight := 0
+	for _, w := range weights {
+		totalWeight += w
+	}
+
+	fmt.Printf("%d lanes of %d, producers PutBatch(%d), consumer GetBatch(%d), %d items per mode\n\n",
+		len(weights), laneSize, putBatch, getBatch, numServed)
+
+	starved := false
+	for _, mode := range []buffer.Mode{buffer.Strict, buffer.Weighted} {
+		res := run(mode)
+		fmt.Printf("%s (%d wakeups, %.1f items each)\n",
+			mode, res.wakeups, float64(numServed)/float64(res.wakeups))
+		for i, n := range res.served {
+			fmt.Printf("  %-7s served %6d  (%5.1f%%, weight %d)\n",
+				laneNames[i], n, 100*float64(n)/numServed, weights[i])
+		}
+		fmt.Printf("  longest run without serving low: %d items\n", res.maxGap)
+
+		// Under weighted mode the low lane must get roughly its share and
+		// never wait longer than one full round of every lane's weight
+		if mode == buffer.Weighted {
+			lowShare := float64(res.served[len(weights)-1]) / numServed
+			want := float64(weights[len(weights)-1]) / float64(totalWeight)
+			if lowShare < want/2 || res.maxGap > 2*totalWeight {
+				fmt.Printf("  FAIL: low lane starved (share %.3f, want ~%.3f; gap %d, limit %d)\n",
+					lowShare, want, res.maxGap, 2*totalWeight)
+				starved = true
+			} else {
+				fmt.Printf("  PASS: low lane not starved (share %.3f, want ~%.3f)\n", lowShare, want)
+			}
+		}
+		fmt.Println()
+	}
+
+	if starved {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,119 @@
+// Lab Six - Producer-Consumer (Go Port)
+// Description: Telemetry-style pipeline comparing buffer overflow policies
+//              A fast producer feeds a slow consumer through a small buffer;
+//              each policy decides what happens to the excess
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"prodcon/buffer"
+)
+
+// ==================== CONFIGURATION ====================
+const (
+	size        = 10                    // Buffer capacity
+	numSamples  = 500                   // Samples emitted by the producer
+	burst       = 10                    // Samples emitted back to back
+	burstGap    = time.Millisecond      // Pause between bursts (~10000 samples/s)
+	consumeCost = time.Millisecond      // Consumer time per sample (~1000 samples/s)
+	putTimeout  = 2 * time.Millisecond  // BlockTimeout wait limit
+	idleTimeout = 50 * time.Millisecond // Consumer gives up after this long idle
+)
+
+// =======================================================
+
+// result summarises one policy run
+type result struct {
+	policy    buffer.Policy
+	stats     buffer.Stats
+	delivered int
+	lastSeq   int // Highest sample the consumer saw
+	elapsed   time.Duration
+}
+
+// runPolicy streams numSamples samples through a buffer with the given policy
+// Parameters:
+//   - policy: Overflow policy under test
+//
+// Returns:
+//   - Counters and delivery figures for the run
+func runPolicy(policy buffer.Policy) result {
+	buf := buffer.NewPolicyBuffer[int](size, policy, putTimeout)
+	res := result{policy: policy, lastSeq: -1}
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	// Producer: emits samples at a fixed rate regardless of the consumer
+	wg.Go(func() {
+		for seq := range numSamples {
+			err := buf.Offer(seq)
+			if err != nil && !errors.Is(err, buffer.ErrFull) && !errors.Is(err, buffer.ErrTimeout) {
+				fmt.Println("unexpected error:", err)
+			}
+			if seq%burst == burst-1 {
+				time.Sleep(burstGap)
+			}
+		}
+	})
+
+	// Consumer: slower than the producer, stops once the stream goes quiet
+	wg.Go(func() {
+		for {
+			seq, err := buf.GetTimeout(idleTimeout)
+			if err != nil {
+				return
+			}
+			res.delivered++
+			res.lastSeq = seq
+			time.Sleep(consumeCost)
+		}
+	})
+
+	wg.Wait()
+	res.elapsed = time.Since(start)
+	res.stats = buf.Stats()
+	return res
+}
+
+// main runs every policy and prints the counters side by side
+func main() {
+	policies := []buffer.Policy{
+		buffer.Block,
+		buffer.DropNewest,
+		buffer.DropOldest,
+		buffer.Reject,
+		buffer.BlockTimeout,
+	}
+
+	fmt.Printf("%d samples, buffer %d, producer bursts of %d every %v, consumer %v per sample\n\n",
+		numSamples, size, burst, burstGap, consumeCost)
+	fmt.Printf("%-14s %8s %9s %12s %8s %9s %9s %8s %9s\n",
+		"policy", "offered", "delivered", "drop-newest", "evicted", "rejected", "timed-out", "last", "elapsed")
+
+	ok := true
+	for _, p := range policies {
+		r := runPolicy(p)
+		s := r.stats
+		fmt.Printf("%-14s %8d %9d %12d %8d %9d %9d %8d %9v\n",
+			r.policy, s.Offered, r.delivered, s.DroppedNewest, s.EvictedOldest,
+			s.Rejected, s.TimedOut, r.lastSeq, r.elapsed.Round(time.Millisecond))
+
+		// Every offered sample is either delivered or counted as dropped
+		if s.Offered != uint64(r.delivered)+s.Dropped() {
+			fmt.Printf("  accounting error: offered %d != delivered %d + dropped %d\n",
+				s.Offered, r.delivered, s.Dropped())
+			ok = false
+		}
+	}
+
+	if !ok {
+		os.Exit(1)
+	}
+	fmt.Println("\nEvery sample accounted for (delivered + dropped == offered)")
+}